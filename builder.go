@@ -2,13 +2,24 @@ package pgsqlbuilder
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 )
 
 // Builder reflects the object to generate and cache PostgreSQL queries (CREATE TABLE, INSERT, UPDATE etc.).
-// Database table and column names are lowercase with underscore and they are generated from field names.
+// Database table and column names are generated from struct and field names by Naming, lowercase with underscore by default.
 type Builder struct {
-	tagName string
-	flags   int64
+	tagName           string
+	flags             int64
+	prefixPrimaryKey  bool
+	strict            bool
+	autoTimestamps    bool
+	softDelete        bool
+	naming            Naming
+	structName        string
+	explicitTableName string
+	schema            string
+	references        map[string]*Builder
 
 	queryCreateTable            string
 	queryDropTable              string
@@ -17,21 +28,51 @@ type Builder struct {
 	queryInsertOnConflictUpdate string
 	querySelectByID             string
 	queryDeleteByID             string
+	queryHardDeleteByID         string
+	queryRestoreByID            string
 	querySelectPrefix           string
 	querySelectCountPrefix      string
 	queryDeletePrefix           string
 	queryUpdatePrefix           string
 
-	tableName string
-
-	fieldColumnName   map[string]string
-	columnFieldName   map[string]string
-	fieldFlags        map[string]int64
-	fieldColumnType   map[string]string
-	fieldDefault      map[string]string
-	columnDefinitions []string
-	columnNames       []string
-
+	tableName        string
+	tableBaseName    string
+	primaryKeyColumn string
+
+	fieldColumnName     map[string]string
+	columnFieldName     map[string]string
+	fieldFlags          map[string]int64
+	fieldColumnType     map[string]string
+	fieldDefault        map[string]string
+	fieldPrimaryKeyType map[string]string
+	fieldTypes          map[string]reflect.Type
+	fieldForeignKey     map[string]*foreignKey
+	fieldIndexMethod    map[string]string
+	fieldIndexWhere     map[string]string
+	indexes             []*index
+	uniqueGroups        map[string][]string
+	uniqueGroupNames    []string
+	fieldChecks         map[string]string
+	checks              []*check
+	fieldEnumValues     map[string][]string
+	fieldEnum           map[string]*enum
+	enums               []*enum
+	insertFields        []string
+	updateFields        []string
+	fieldGenerated      map[string]string
+	fieldComment        map[string]string
+	fieldAutoValue      map[string]string
+	softDeleteField     string
+	softDeleteValue     string
+	deletedScope        int
+	versionField        string
+	tableComment        string
+	columnDefinitions   []string
+	columnNames         []string
+	fieldNames          []string
+	primaryKeyFields    []string
+
+	warnings     []error
 	reflectError error
 }
 
@@ -43,16 +84,50 @@ func New(obj interface{}, options Options) *Builder {
 	if options.TagName != "" {
 		builder.tagName = options.TagName
 	}
+	builder.naming = options.Naming
+	if builder.naming == nil {
+		builder.naming = DefaultNaming{}
+	}
+	builder.schema = options.Schema
+	builder.structName = options.StructName
+	builder.explicitTableName = options.TableName
+	builder.prefixPrimaryKey = options.PrefixPrimaryKey
+	builder.strict = options.Strict
+	builder.autoTimestamps = options.AutoTimestamps
+	builder.softDelete = options.SoftDelete
+	builder.references = options.References
 
 	builder.reflect(obj, options.TableNamePrefix)
 	return builder
 }
 
+// WithSchema returns a copy of the Builder with queries for the table in another schema.
+// The struct is not reflected again, so it is cheap to call it for every tenant schema.
+func (b *Builder) WithSchema(schema string) *Builder {
+	builder := *b
+	builder.schema = schema
+	builder.buildQueries()
+
+	return &builder
+}
+
+// Schema returns the schema of the table, or an empty string when the table is not schema-qualified.
+func (b *Builder) Schema() string {
+	return b.schema
+}
+
 // Err returns an error that appeared during reflecting the struct.
 func (b *Builder) Err() error {
 	return b.reflectError
 }
 
+// Warnings returns problems found when reflecting the struct, which did not stop the Builder, eg. unsupported fields,
+// unknown tag options or a missing primary key.  Each of them is a *BuilderError.  With Strict option, they are returned
+// by Err() as well.
+func (b *Builder) Warnings() []error {
+	return b.warnings
+}
+
 // Flags returns flags.
 func (b *Builder) Flags() int64 {
 	return b.flags
@@ -74,23 +149,76 @@ func (b *Builder) Insert() string {
 }
 
 // UpdateByID returns an SQL query for updating an object by their ID.
+// When the struct has a version field, the row is updated only at the expected version, and the incremented version is returned.
+// Empty string is returned when there is no primary key or no column to update.
 func (b *Builder) UpdateByID() string {
-	return b.queryUpdateByID + ";"
+	return queryStatement(b.queryUpdateByID)
 }
 
 // InsertOnConflictUpdate returns an SQL query for inserting when conflict is detected.
+// Empty string is returned when there is no primary key.
 func (b *Builder) InsertOnConflictUpdate() string {
-	return b.queryInsertOnConflictUpdate + ";"
+	return queryStatement(b.queryInsertOnConflictUpdate)
+}
+
+// InsertOnConflict returns an SQL query for inserting a new object, with an action taken when it conflicts with an existing row.
+// Target is a name of unique group from UniqueGroups(), a unique field from UniqueFields() or a primary key field.
+// Values are passed the same way as for Insert().
+func (b *Builder) InsertOnConflict(target string, action ConflictAction) (string, error) {
+	targetFields, err := b.conflictTargetFields(target)
+	if err != nil {
+		return "", getClauseBuilderError("on conflict", "target", err)
+	}
+
+	qAction, err := b.queryConflictAction(targetFields, action)
+	if err != nil {
+		return "", getClauseBuilderError("on conflict", "action", err)
+	}
+
+	insertColumns := b.fieldsColumns(b.insertFields)
+	autoColumns, autoValues := b.autoValueColumns(false)
+	query := fmt.Sprintf("INSERT INTO %s(%s) VALUES (%s) ON CONFLICT (%s) %s", b.tableName, strings.Join(append(insertColumns, autoColumns...), ","),
+		placeholdersWithValues(len(insertColumns), 1, autoValues), strings.Join(b.fieldsColumns(targetFields), ","), qAction)
+	if b.primaryKeyColumn != "" {
+		query += " RETURNING " + joinNotEmpty(",", b.primaryKeyColumn, b.versionColumn())
+	}
+
+	return query + ";", nil
 }
 
 // SelectByID returns an SQL query for selecting object by its ID.
+// Soft deleted rows are not selected, unless the Builder is returned by WithDeleted() or OnlyDeleted().
+// Empty string is returned when there is no primary key.
 func (b *Builder) SelectByID() string {
-	return b.querySelectByID + ";"
+	return queryStatement(b.querySelectByID)
 }
 
 // DeleteByID returns an SQL query for deleting object by its ID.
+// When the struct has a soft delete field, the query sets it instead of removing the row.
+// Empty string is returned when there is no primary key.
 func (b *Builder) DeleteByID() string {
-	return b.queryDeleteByID + ";"
+	return queryStatement(b.queryDeleteByID)
+}
+
+// SelectByPK returns an SQL query for selecting object by its primary key, which can be composite.
+// Values of the primary key fields are passed in the same order as the fields are defined in the struct.
+// Empty string is returned when there is no primary key.
+func (b *Builder) SelectByPK() string {
+	return queryStatement(b.querySelectByID)
+}
+
+// UpdateByPK returns an SQL query for updating an object by its primary key, which can be composite.
+// Values of the primary key fields are passed after the updated values, in the same order as the fields are defined in the struct.
+// Empty string is returned when there is no primary key or no column to update.
+func (b *Builder) UpdateByPK() string {
+	return queryStatement(b.queryUpdateByID)
+}
+
+// DeleteByPK returns an SQL query for deleting object by its primary key, which can be composite.
+// Values of the primary key fields are passed in the same order as the fields are defined in the struct.
+// Empty string is returned when there is no primary key.
+func (b *Builder) DeleteByPK() string {
+	return queryStatement(b.queryDeleteByID)
 }
 
 // Select returns a SELECT query with WHERE condition built from 'filters' (field-value pairs).
@@ -110,6 +238,7 @@ func (b *Builder) Select(order []string, limit int, offset int, filters *Filters
 		return "", getClauseBuilderError("where", "filters", err)
 	}
 
+	qWhere = andCondition(qWhere, b.scopeCondition())
 	if qWhere != "" {
 		query += " WHERE " + qWhere
 	}
@@ -133,6 +262,7 @@ func (b *Builder) SelectCount(filters *Filters) (string, error) {
 		return "", getClauseBuilderError("where", "filters", err)
 	}
 
+	qWhere = andCondition(qWhere, b.scopeCondition())
 	if qWhere != "" {
 		query += " WHERE " + qWhere
 	}
@@ -141,6 +271,7 @@ func (b *Builder) SelectCount(filters *Filters) (string, error) {
 }
 
 // Delete returns a DELETE query with WHERE condition built from 'filters' (field-value pairs).
+// When the struct has a soft delete field, it is an UPDATE query setting the field on rows that are not deleted yet.
 // Struct fields in 'filters' argument are sorted alphabetically. Hence, when used with database connection, their values (or pointers to it) must be sorted as well.
 func (b *Builder) Delete(filters *Filters) (string, error) {
 	query := b.queryDeletePrefix
@@ -150,6 +281,7 @@ func (b *Builder) Delete(filters *Filters) (string, error) {
 		return "", getClauseBuilderError("where", "filters", err)
 	}
 
+	qWhere = andCondition(qWhere, b.deletedCondition(false))
 	if qWhere != "" {
 		query += " WHERE " + qWhere
 	}
@@ -167,16 +299,22 @@ func (b *Builder) DeleteReturningID(filters *Filters) (string, error) {
 		return "", getClauseBuilderError("where", "filters", err)
 	}
 
+	qWhere = andCondition(qWhere, b.deletedCondition(false))
 	if qWhere != "" {
 		query += " WHERE " + qWhere
 	}
-	query += fmt.Sprintf(` RETURNING "%s";`, b.fieldColumnName["ID"])
+
+	if b.primaryKeyColumn == "" {
+		return "", getClauseBuilderError("returning", "primary key", noPrimaryKeyError)
+	}
+	query += fmt.Sprintf(` RETURNING %s;`, b.primaryKeyColumn)
 
 	return query, nil
 }
 
 // Update returns an UPDATE query where specified struct fields (columns) are updated and rows match specific WHERE condition built from 'filters' (field-value pairs).
 // Struct fields in 'values' and 'filters' arguments, are sorted alphabetically. Hence, when used with database connection, their values (or pointers to it) must be sorted as well.
+// When the struct has a version field, it is incremented, and its value in 'values' is the expected version of the rows.
 func (b *Builder) Update(values map[string]interface{}, filters *Filters) (string, error) {
 	query := b.queryUpdatePrefix
 
@@ -192,9 +330,17 @@ func (b *Builder) Update(values map[string]interface{}, filters *Filters) (strin
 		return "", getClauseBuilderError("where", "filters", err)
 	}
 
+	// Version in values is the expected one, and the incremented version is returned
+	versionNumber := b.versionValueNumber(values)
+	if versionNumber > 0 {
+		qWhere = andCondition(qWhere, fmt.Sprintf("%s=$%d", b.versionColumn(), versionNumber))
+	}
 	if qWhere != "" {
 		query += " WHERE " + qWhere
 	}
+	if versionNumber > 0 {
+		query += " RETURNING " + b.versionColumn()
+	}
 
 	return query + ";", nil
 }
@@ -204,7 +350,26 @@ func (b *Builder) DatabaseColumnToFieldName(n string) string {
 	return b.columnFieldName[n]
 }
 
-// HasModificationFields returns true if all the following int64 fields are present: CreatedAt, CreatedBy, ModifiedAt, ModifiedBy.
+// StructFieldValueFromString takes a field value as string and converts it (if possible) to a value type of that field,
+// the same way as StructFieldValueFromString function, resolving fields and reading their tags with the tag set in TagName option.
+func (b *Builder) StructFieldValueFromString(obj interface{}, name string, value string) (bool, interface{}) {
+	return fieldValueFromString(obj, name, value, b.tagName)
+}
+
+// IsStructField checks if a field exists in a struct, the same way as IsStructField function, resolving fields with the tag
+// set in TagName option.
+func (b *Builder) IsStructField(obj interface{}, field string) bool {
+	return isStructFieldWithTag(obj, field, b.tagName)
+}
+
+// SetObjFields sets fields of the object to values from the filters, the same way as SetObjFields function, resolving fields
+// with the tag set in TagName option.
+func (b *Builder) SetObjFields(obj interface{}, values *Filters) error {
+	return setObjFieldsWithTag(obj, values, b.tagName)
+}
+
+// HasModificationFields returns true if all the following fields are present: CreatedAt, CreatedBy, ModifiedAt, ModifiedBy.
+// They are int64 fields, and timestamps can be time.Time fields as well.
 func (b *Builder) HasModificationFields() bool {
 	return b.flags&FlagHasModificationFields > 0
 }
@@ -221,6 +386,33 @@ func (b *Builder) UniqueFields() []string {
 	return uniqFields
 }
 
+// PrimaryKeyFields returns a list with field names that make the primary key.
+func (b *Builder) PrimaryKeyFields() []string {
+	return b.primaryKeyFields
+}
+
+// UniqueGroups returns a map with names of unique groups and their field names, which make composite unique constraints.
+func (b *Builder) UniqueGroups() map[string][]string {
+	groups := make(map[string][]string, len(b.uniqueGroups))
+	for group, fieldNames := range b.uniqueGroups {
+		groups[group] = append([]string{}, fieldNames...)
+	}
+
+	return groups
+}
+
+// InsertFields returns a list with field names in the order of values in Insert query.
+// Generated columns and primary key generated by the database are not there.
+func (b *Builder) InsertFields() []string {
+	return b.insertFields
+}
+
+// UpdateFields returns a list with field names in the order of values in UpdateByID query, which are followed by the primary key
+// and the expected version, when the struct has a version field.
+func (b *Builder) UpdateFields() []string {
+	return b.updateFields
+}
+
 // PasswordFields returns a list with field names that are passwords.
 func (b *Builder) PasswordFields() []string {
 	passFields := make([]string, 0, len(b.fieldColumnName))
@@ -1,11 +1,15 @@
 package pgsqlbuilder
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 func (b *Builder) initMaps(numField int) {
@@ -14,13 +18,62 @@ func (b *Builder) initMaps(numField int) {
 	b.fieldFlags = make(map[string]int64, numField)
 	b.fieldColumnType = make(map[string]string, numField)
 	b.fieldDefault = make(map[string]string, numField)
+	b.fieldPrimaryKeyType = make(map[string]string, 1)
+	b.fieldTypes = make(map[string]reflect.Type, numField)
+	b.fieldForeignKey = make(map[string]*foreignKey)
+	b.fieldIndexMethod = make(map[string]string)
+	b.fieldIndexWhere = make(map[string]string)
+	b.indexes = make([]*index, 0)
+	b.uniqueGroups = make(map[string][]string)
+	b.uniqueGroupNames = make([]string, 0)
+	b.fieldChecks = make(map[string]string)
+	b.checks = make([]*check, 0)
+	b.fieldEnumValues = make(map[string][]string)
+	b.fieldGenerated = make(map[string]string)
+	b.fieldComment = make(map[string]string)
+	b.fieldAutoValue = make(map[string]string)
+	b.fieldEnum = make(map[string]*enum)
+	b.enums = make([]*enum, 0)
 	b.columnDefinitions = make([]string, 0, numField)
 	b.columnNames = make([]string, 0, numField)
+	b.fieldNames = make([]string, 0, numField)
+	b.primaryKeyFields = make([]string, 0, 1)
 }
 
 func (b *Builder) reflect(obj interface{}, tableNamePrefix string) {
 	b.reflectFieldTags(obj)
 	b.reflectBuildQueries(obj, tableNamePrefix)
+
+	// Queries by ID are not available without a primary key, and there is no UpdateByID when nothing can be updated
+	if b.reflectError == nil {
+		switch {
+		case len(b.primaryKeyFields) == 0:
+			b.warnings = append(b.warnings, getTableBuilderError(b.tableBaseName, noPrimaryKeyError))
+		case b.queryUpdateByID == "":
+			b.warnings = append(b.warnings, getTableBuilderError(b.tableBaseName, noUpdateColumnsError))
+		}
+	}
+
+	// In strict mode, warnings are errors
+	if b.strict && len(b.warnings) > 0 {
+		b.reflectError = errors.Join(append([]error{b.reflectError}, b.warnings...)...)
+	}
+}
+
+// addWarning records a problem with a field that does not stop the builder, eg. an unknown tag option.
+func (b *Builder) addWarning(fieldName string, err error) {
+	b.warnings = append(b.warnings, getTagBuilderError(fieldName, b.tagName, err))
+}
+
+// addError records a problem that makes the builder unusable.  Reflection goes on after it, so that all the problems
+// are reported at once.
+func (b *Builder) addError(err error) {
+	if b.reflectError == nil {
+		b.reflectError = err
+		return
+	}
+
+	b.reflectError = errors.Join(b.reflectError, err)
 }
 
 func (b *Builder) reflectFieldTags(obj interface{}) {
@@ -28,16 +81,21 @@ func (b *Builder) reflectFieldTags(obj interface{}) {
 	objIndirectValue := reflect.Indirect(objValue)
 	objType := objIndirectValue.Type()
 
-	b.initMaps(objType.NumField())
+	fields, skipped := structFieldsWithSkipped(objType, b.tagName)
+	b.initMaps(len(fields))
 
-	for j := 0; j < objType.NumField(); j++ {
-		field := objType.Field(j)
-		fieldTypeKind := field.Type.Kind()
+	for _, sf := range skipped {
+		b.addWarning(sf.name, fmt.Errorf("%w: %s", unsupportedFieldError, sf.field.Type))
+	}
 
-		// Only basic golang types are included as columns for the database table.
-		// Check the function below for the details.
-		if !IsFieldKindSupported(fieldTypeKind) {
-			continue
+	hasID := false
+	for _, sf := range fields {
+		// Fields of embedded and nested structs are named the way they are referred to in filters, eg. Address.City
+		field := sf.field
+		field.Name = sf.name
+
+		if field.Name == "ID" {
+			hasID = true
 		}
 
 		// Get value of field's sql and sql_val tags ('2sql' or different when TagName provided in options).
@@ -45,14 +103,52 @@ func (b *Builder) reflectFieldTags(obj interface{}) {
 		valTagValue := field.Tag.Get(b.tagName + "_val")
 
 		// Go through tag values and parse out the ones we're interested in.
-		b.setFieldFromTag(tagValue, field.Name)
-		if b.reflectError != nil {
-			return
+		// Pointer fields are configured the same way as fields of the type they point to.
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
 		}
+		b.fieldTypes[field.Name] = fieldType
+		b.setFieldFromTag(tagValue, field.Name, fieldType)
 
 		if valTagValue != "" {
 			b.fieldDefault[field.Name] = valTagValue
 		}
+
+		// Version cannot be NULL, as it would not be incremented
+		if b.versionField == field.Name && field.Type.Kind() == reflect.Ptr {
+			b.addError(getTagBuilderError(field.Name, b.tagName, fmt.Errorf("%w: pointer field cannot be a version", versionError)))
+		}
+
+		if b.fieldFlags[field.Name]&FieldFlagPrimaryKey == 0 {
+			continue
+		}
+
+		// Primary key cannot be NULL
+		if field.Type.Kind() == reflect.Ptr {
+			b.addError(getTagBuilderError(field.Name, b.tagName, fmt.Errorf("%w: pointer field cannot be a primary key", primaryKeyError)))
+			continue
+		}
+
+		b.primaryKeyFields = append(b.primaryKeyFields, field.Name)
+	}
+
+	// When there is no field tagged as a primary key, 'ID' field is the one
+	if len(b.primaryKeyFields) == 0 && hasID && b.fieldFlags["ID"]&FieldFlagPrimaryKey == 0 {
+		b.primaryKeyFields = append(b.primaryKeyFields, "ID")
+		b.fieldFlags["ID"] += FieldFlagPrimaryKey
+	}
+
+	// Single integer primary key is serial by default, and composite primary key is made of values set by the application
+	for _, fieldName := range b.primaryKeyFields {
+		if b.fieldPrimaryKeyType[fieldName] != "" {
+			continue
+		}
+
+		b.fieldPrimaryKeyType[fieldName] = primaryKeyNatural
+		if len(b.primaryKeyFields) == 1 && isIntegerKind(b.fieldTypes[fieldName].Kind()) {
+			b.fieldPrimaryKeyType[fieldName] = primaryKeySerial
+		}
 	}
 }
 
@@ -66,54 +162,86 @@ func (b *Builder) reflectBuildQueries(obj interface{}, tableNamePrefix string) {
 	}
 
 	objTypeName := objType.Name()
-	// if struct is User_Register, then take User as base for table name.
-	if strings.Contains(objTypeName, "_") {
-		objTypeName = strings.Split(objTypeName, "_")[0]
+	if b.structName != "" {
+		objTypeName = b.structName
 	}
 
-	b.tableName = fmt.Sprintf(`"%s"`, tableNamePrefix+FieldToColumn(objTypeName))
+	// Table name set in options or returned by TableName method of the struct overwrites the one from the struct name
+	tableName := b.naming.TableName(objTypeName)
+	if b.explicitTableName != "" {
+		tableName = b.explicitTableName
+	} else if namer, ok := reflect.New(objType).Interface().(tableNamer); ok && namer.TableName() != "" {
+		tableName = namer.TableName()
+	}
 
-	var (
-		columnNames                string
-		columnNamesWithoutID       string
-		valuesWithoutID            string
-		values                     string
-		columnNamesWithValues      string
-		columnNamesWithValuesAgain string
-		numColumn                  int
-		modificationFields         int
-	)
+	b.tableBaseName = tableNamePrefix + tableName
+
+	if commenter, ok := reflect.New(objType).Interface().(tableCommenter); ok {
+		b.tableComment = commenter.TableComment()
+	}
 
-	for j := 0; j < objType.NumField(); j++ {
-		field := objType.Field(j)
+	modificationFields := 0
+	for _, sf := range structFields(objType, b.tagName) {
+		field := sf.field
+		field.Name = sf.name
 		fieldTypeKind := field.Type.Kind()
 
-		// Only basic golang types are included as columns for the database table.
-		// Check the function below for the details.
-		if !IsFieldKindSupported(fieldTypeKind) {
-			continue
+		fieldType := field.Type
+		if fieldTypeKind == reflect.Ptr {
+			fieldType = fieldType.Elem()
+			fieldTypeKind = fieldType.Kind()
+
+			if b.fieldFlags[field.Name]&FieldFlagNullable == 0 {
+				b.fieldFlags[field.Name] += FieldFlagNullable
+			}
+		}
+
+		if isJSONType(fieldType) && b.fieldFlags[field.Name]&FieldFlagJSON == 0 {
+			b.fieldFlags[field.Name] += FieldFlagJSON
+		}
+
+		if fieldTypeKind == reflect.Slice && fieldType.Elem().Kind() != reflect.Uint8 && b.fieldFlags[field.Name]&(FieldFlagArray|FieldFlagJSON) == 0 {
+			b.fieldFlags[field.Name] += FieldFlagArray
 		}
 
 		if fieldTypeKind != reflect.String && b.fieldFlags[field.Name]&FieldFlagNotString == 0 {
 			b.fieldFlags[field.Name] += FieldFlagNotString
 		}
 
-		columnName := FieldToColumn(field.Name)
-		b.fieldColumnName[field.Name] = columnName
+		// Column name can be set with a tag, and otherwise primary key column can be prefixed with the table name without
+		// TableNamePrefix, eg. product_id
+		columnName, ok := b.fieldColumnName[field.Name]
+		if !ok {
+			columnName = sf.columnPrefix + b.naming.ColumnName(sf.field.Name)
+			if b.fieldFlags[field.Name]&FieldFlagPrimaryKey > 0 && b.prefixPrimaryKey {
+				columnName = tableName + "_" + columnName
+			}
+			b.fieldColumnName[field.Name] = columnName
+		}
+
+		_, ok = b.columnFieldName[columnName]
+		if ok {
+			b.addError(getTagBuilderError(field.Name, b.tagName, fmt.Errorf("%w: %s", duplicateColumnError, columnName)))
+		}
 		b.columnFieldName[columnName] = field.Name
 
+		// ENUM column is compared as a string only after casting it to TEXT
+		b.setFieldEnum(field.Name, fieldType, columnName)
+		if b.fieldEnum[field.Name] != nil && b.fieldFlags[field.Name]&FieldFlagNotString == 0 {
+			b.fieldFlags[field.Name] += FieldFlagNotString
+		}
+
 		unique := false
 		if b.fieldFlags[field.Name]&FieldFlagUnique > 0 {
 			unique = true
 		}
 
-		columnDefinition := b.columnDefinitionFromField(field.Name, field.Type.String(), unique)
+		columnDefinition := b.columnDefinitionFromField(field.Name, fieldType, unique)
 		b.columnDefinitions = append(b.columnDefinitions, fmt.Sprintf(`"%s" %s`, columnName, columnDefinition))
 		b.columnNames = append(b.columnNames, fmt.Sprintf(`"%s"`, columnName))
+		b.fieldNames = append(b.fieldNames, field.Name)
 
-		// Assuming that primary field is named ID and that it is always first -> TODO: add check
-
-		if b.isFieldModification(field.Name, fieldTypeKind) {
+		if b.isFieldModification(field.Name, fieldType) {
 			modificationFields++
 		}
 	}
@@ -122,126 +250,480 @@ func (b *Builder) reflectBuildQueries(obj interface{}, tableNamePrefix string) {
 		b.flags += FlagHasModificationFields
 	}
 
-	// Assuming that struct has at least 2 fields -> TODO: add check
-	numColumn = len(b.columnNames)
+	if b.autoTimestamps {
+		b.setModificationFields()
+	}
+	b.setSoftDeleteField()
+
+	b.reflectGenerated()
+	b.reflectChecks()
+	b.reflectIndexes()
+
+	b.buildQueries()
+}
+
+// buildQueries generates queries from the reflected table and columns.
+func (b *Builder) buildQueries() {
+	var (
+		insertColumns     []string
+		updateColumns     []string
+		upsertColumns     []string
+		primaryKeyColumns []string
+		overriding        string
+	)
+
+	b.tableName = qualifiedName(b.schema, b.tableBaseName)
+
+	b.insertFields = make([]string, 0, len(b.fieldNames))
+	b.updateFields = make([]string, 0, len(b.fieldNames))
+	for i, fieldName := range b.fieldNames {
+		// Generated and read-only columns are never written, and insert-only columns are not updated.
+		// Values of columns such as CreatedAt, and version, are set by the database, so they do not have placeholders.
+		_, isAutoValue := b.fieldAutoValue[fieldName]
+		if b.fieldFlags[fieldName]&(FieldFlagGenerated|FieldFlagReadOnly|FieldFlagVersion) > 0 || isAutoValue {
+			continue
+		}
+
+		upsertColumns = append(upsertColumns, b.columnNames[i])
+
+		if b.fieldFlags[fieldName]&FieldFlagPrimaryKey == 0 {
+			insertColumns = append(insertColumns, b.columnNames[i])
+			b.insertFields = append(b.insertFields, fieldName)
+			if b.fieldFlags[fieldName]&FieldFlagInsertOnly == 0 {
+				updateColumns = append(updateColumns, b.columnNames[i])
+				b.updateFields = append(b.updateFields, fieldName)
+			}
+			continue
+		}
+
+		primaryKeyColumns = append(primaryKeyColumns, b.columnNames[i])
 
-	columnNamesWithoutID = strings.Join(b.columnNames[1:], ",")
-	columnNames = strings.Join(b.columnNames, ",")
-	valuesWithoutID = "?" + strings.Repeat(",?", numColumn-2)
+		// Value of a generated primary key is not inserted, and identity requires overriding it in upsert
+		switch b.fieldPrimaryKeyType[fieldName] {
+		case primaryKeyNatural:
+			insertColumns = append(insertColumns, b.columnNames[i])
+			b.insertFields = append(b.insertFields, fieldName)
+		case primaryKeyIdentity:
+			overriding = " OVERRIDING SYSTEM VALUE"
+		}
+	}
 
-	columnNamesWithValues = strings.Join(b.columnNames[1:], "=?,") + "=?"
-	columnNamesWithValuesAgain = columnNamesWithValues
-	for i := 1; i <= numColumn*2; i++ {
-		columnNamesWithValues = strings.Replace(columnNamesWithValues, "?", fmt.Sprintf("$%d", i), 1)
-		valuesWithoutID = strings.Replace(valuesWithoutID, "?", fmt.Sprintf("$%d", i), 1)
-		if i > numColumn {
-			columnNamesWithValuesAgain = strings.Replace(columnNamesWithValuesAgain, "?", fmt.Sprintf("$%d", i), 1)
+	numColumn := len(upsertColumns)
+	columnNames := strings.Join(b.columnNames, ",")
+	primaryKeyColumn := strings.Join(primaryKeyColumns, ",")
+	b.primaryKeyColumn = primaryKeyColumn
+
+	// References depend on the schema, so they are added to the column definitions here
+	tableDefinitions := make([]string, 0, len(b.columnDefinitions)+len(b.uniqueGroupNames)+len(b.checks)+1)
+	for i, fieldName := range b.fieldNames {
+		definition := b.enumColumnDefinition(fieldName, b.columnDefinitions[i])
+
+		fk, isForeignKey := b.fieldForeignKey[fieldName]
+		if isForeignKey {
+			references, err := b.foreignKeyDefinition(fk, b.fieldFlags[fieldName]&FieldFlagNullable > 0)
+			if err != nil && b.reflectError == nil {
+				b.reflectError = getTagBuilderError(fieldName, b.tagName, err)
+			}
+			definition += " " + references
 		}
+
+		tableDefinitions = append(tableDefinitions, definition)
 	}
-	values = valuesWithoutID + fmt.Sprintf(",$%d", numColumn)
 
-	idColumn := `"id"`
+	// Composite primary key, unique groups and checks are table constraints
+	if len(primaryKeyColumns) > 1 {
+		tableDefinitions = append(tableDefinitions, fmt.Sprintf("PRIMARY KEY (%s)", primaryKeyColumn))
+	}
+	for _, group := range b.uniqueGroupNames {
+		tableDefinitions = append(tableDefinitions, fmt.Sprintf("CONSTRAINT %s UNIQUE (%s)",
+			quoteIdentifier(shortIdentifier(b.tableBaseName+"_"+group+"_key")), strings.Join(b.fieldsColumns(b.uniqueGroups[group]), ",")))
+	}
+	tableDefinitions = append(tableDefinitions, b.tableChecks()...)
 
 	b.queryDropTable = fmt.Sprintf("DROP TABLE IF EXISTS %s", b.tableName)
-	b.queryCreateTable = fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", b.tableName, strings.Join(b.columnDefinitions, ","))
+	b.queryCreateTable = fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", b.tableName, strings.Join(tableDefinitions, ","))
 
-	b.queryDeleteByID = fmt.Sprintf("DELETE FROM %s WHERE %s = $1", b.tableName, idColumn)
 	b.queryDeletePrefix = fmt.Sprintf("DELETE FROM %s", b.tableName)
-
-	b.queryUpdateByID = fmt.Sprintf("UPDATE %s SET %s WHERE %s = $%d", b.tableName, columnNamesWithValues, idColumn, numColumn)
+	if b.softDeleteField != "" {
+		b.queryDeletePrefix = fmt.Sprintf("UPDATE %s SET %s", b.tableName, b.softDeleteSet(false))
+	}
 	b.queryUpdatePrefix = fmt.Sprintf("UPDATE %s SET", b.tableName)
+	b.querySelectPrefix = fmt.Sprintf("SELECT %s FROM %s", columnNames, b.tableName)
+	b.querySelectCountPrefix = fmt.Sprintf("SELECT COUNT(*) AS cnt FROM %s", b.tableName)
 
-	b.queryInsert = fmt.Sprintf("INSERT INTO %s(%s) VALUES (%s) RETURNING %s", b.tableName, columnNamesWithoutID, valuesWithoutID, idColumn)
-	b.queryInsertOnConflictUpdate = fmt.Sprintf("INSERT INTO %s(%s) VALUES (%s) ON CONFLICT (%s) DO UPDATE SET %s RETURNING %s", b.tableName, columnNames, values, idColumn, columnNamesWithValuesAgain, idColumn)
+	// Columns with values set by the database, such as CreatedAt, follow the ones with placeholders
+	autoColumns, autoValues := b.autoValueColumns(false)
+	autoSet := b.autoValueSet(false)
+	insertColumnNames := strings.Join(append(insertColumns[:len(insertColumns):len(insertColumns)], autoColumns...), ",")
+	upsertColumnNames := strings.Join(append(upsertColumns[:len(upsertColumns):len(upsertColumns)], autoColumns...), ",")
 
-	b.querySelectByID = fmt.Sprintf("SELECT %s FROM %s WHERE %s = $1", columnNames, b.tableName, idColumn)
-	b.querySelectPrefix = fmt.Sprintf("SELECT %s FROM %s", columnNames, b.tableName)
+	if insertColumnNames != "" {
+		b.queryInsert = fmt.Sprintf("INSERT INTO %s(%s) VALUES (%s)", b.tableName, insertColumnNames, placeholdersWithValues(len(insertColumns), 1, autoValues))
+	} else {
+		b.queryInsert = fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", b.tableName)
+	}
 
-	b.querySelectCountPrefix = fmt.Sprintf("SELECT COUNT(*) AS cnt FROM %s", b.tableName)
+	// Queries by ID are available only when there is a primary key
+	if primaryKeyColumn == "" {
+		return
+	}
+
+	b.queryInsert += fmt.Sprintf(" RETURNING %s", primaryKeyColumn)
+
+	// With soft delete, rows are deleted and restored by setting the deleted field, and selected depending on the scope
+	primaryKeyCondition := columnsCondition(primaryKeyColumns, 1)
+	b.queryDeleteByID = fmt.Sprintf("DELETE FROM %s WHERE %s", b.tableName, primaryKeyCondition)
+	b.queryHardDeleteByID = fmt.Sprintf("DELETE FROM %s WHERE %s", b.tableName, primaryKeyCondition)
+	b.querySelectByID = fmt.Sprintf("SELECT %s FROM %s WHERE %s", columnNames, b.tableName, andCondition(primaryKeyCondition, b.scopeCondition()))
+	if b.softDeleteField != "" {
+		b.queryDeleteByID = fmt.Sprintf("UPDATE %s SET %s WHERE %s", b.tableName, b.softDeleteSet(false),
+			andCondition(primaryKeyCondition, b.deletedCondition(false)))
+		b.queryRestoreByID = fmt.Sprintf("UPDATE %s SET %s WHERE %s", b.tableName, b.softDeleteSet(true),
+			andCondition(primaryKeyCondition, b.deletedCondition(true)))
+	}
+
+	if len(updateColumns) == 0 && autoSet == "" && b.versionField == "" {
+		b.queryInsertOnConflictUpdate = fmt.Sprintf("INSERT INTO %s(%s)%s VALUES (%s) ON CONFLICT (%s) DO NOTHING RETURNING %s",
+			b.tableName, upsertColumnNames, overriding, placeholdersWithValues(numColumn, 1, autoValues), primaryKeyColumn, primaryKeyColumn)
+		return
+	}
+
+	b.queryUpdateByID = fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+		b.tableName, joinNotEmpty(",", columnsWithPlaceholders(updateColumns, 1), autoSet, b.versionSet(false)), columnsCondition(primaryKeyColumns, len(updateColumns)+1))
+	b.queryInsertOnConflictUpdate = fmt.Sprintf("INSERT INTO %s(%s)%s VALUES (%s) ON CONFLICT (%s) DO UPDATE SET %s",
+		b.tableName, upsertColumnNames, overriding, placeholdersWithValues(numColumn, 1, autoValues), primaryKeyColumn,
+		joinNotEmpty(",", columnsWithPlaceholders(updateColumns, numColumn+1), autoSet, b.versionSet(true)))
+
+	// Expected version follows the primary key in UpdateByID, and the updated values in upsert.  When the row is at another
+	// version, nothing is returned.
+	if b.versionField == "" {
+		b.queryInsertOnConflictUpdate += " RETURNING " + primaryKeyColumn
+		return
+	}
+
+	versionColumn := b.versionColumn()
+	b.queryUpdateByID += fmt.Sprintf(" AND %s = $%d RETURNING %s", versionColumn, len(updateColumns)+len(primaryKeyColumns)+1, versionColumn)
+	b.queryInsertOnConflictUpdate += fmt.Sprintf(" WHERE %s.%s = $%d RETURNING %s,%s", b.tableName, versionColumn, numColumn+len(updateColumns)+1,
+		primaryKeyColumn, versionColumn)
+}
+
+// fieldsColumns returns quoted columns of fields.
+func (b *Builder) fieldsColumns(fieldNames []string) []string {
+	columns := make([]string, 0, len(fieldNames))
+	for _, fieldName := range fieldNames {
+		columns = append(columns, fmt.Sprintf(`"%s"`, b.fieldColumnName[fieldName]))
+	}
+
+	return columns
+}
+
+// placeholders returns a list of numbered placeholders, eg. $1,$2,$3.
+func placeholders(num int, first int) string {
+	return placeholdersWithValues(num, first, nil)
+}
+
+// placeholdersWithValues returns a list of numbered placeholders followed by values, eg. $1,$2,now().
+func placeholdersWithValues(num int, first int, values []string) string {
+	list := make([]string, 0, num+len(values))
+	for i := first; i < first+num; i++ {
+		list = append(list, fmt.Sprintf("$%d", i))
+	}
+
+	return strings.Join(append(list, values...), ",")
+}
+
+// joinNotEmpty joins items that are not empty.
+func joinNotEmpty(sep string, items ...string) string {
+	notEmpty := make([]string, 0, len(items))
+	for _, item := range items {
+		if item != "" {
+			notEmpty = append(notEmpty, item)
+		}
+	}
+
+	return strings.Join(notEmpty, sep)
+}
+
+// queryStatement terminates a query with a semicolon, and returns an empty string for a query that is not available.
+func queryStatement(query string) string {
+	if query == "" {
+		return ""
+	}
+
+	return query + ";"
+}
+
+// columnsCondition returns a condition where all columns are equal to numbered placeholders, eg. "user_id" = $1 AND "group_id" = $2.
+func columnsCondition(columns []string, first int) string {
+	conditions := make([]string, 0, len(columns))
+	for i, column := range columns {
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, first+i))
+	}
+
+	return strings.Join(conditions, " AND ")
 }
 
-func (b *Builder) setFieldFromTag(tag string, fieldName string) {
-	opts := strings.Split(tag, " ")
+// columnsWithPlaceholders returns a list of columns with numbered placeholders, eg. "name"=$1,"age"=$2.
+func columnsWithPlaceholders(columns []string, first int) string {
+	values := make([]string, 0, len(columns))
+	for i, column := range columns {
+		values = append(values, fmt.Sprintf("%s=$%d", column, first+i))
+	}
+
+	return strings.Join(values, ",")
+}
+
+func (b *Builder) setFieldFromTag(tag string, fieldName string, fieldType reflect.Type) {
+	opts := splitTag(tag)
 	for _, opt := range opts {
-		b.setFieldFromTagOptWithoutVal(opt, fieldName)
+		key, val, hasVal := strings.Cut(opt, ":")
+		if hasVal {
+			b.setFieldFromTagOptWithVal(key, val, fieldName, fieldType)
+		} else {
+			b.setFieldFromTagOptWithoutVal(opt, fieldName, fieldType)
+		}
 	}
 }
 
-func (b *Builder) setFieldFromTagOptWithoutVal(opt string, fieldName string) {
-	if opt == "uniq" && b.fieldFlags[fieldName]&FieldFlagUnique == 0 {
-		b.fieldFlags[fieldName] += FieldFlagUnique
-		return
+func (b *Builder) setFieldFromTagOptWithoutVal(opt string, fieldName string, fieldType reflect.Type) {
+	switch opt {
+	case "uniq":
+		if b.fieldFlags[fieldName]&FieldFlagUnique == 0 {
+			b.fieldFlags[fieldName] += FieldFlagUnique
+		}
+	case "pass":
+		if b.fieldFlags[fieldName]&FieldFlagPassword == 0 {
+			b.fieldFlags[fieldName] += FieldFlagPassword
+		}
+	case "readonly":
+		if b.fieldFlags[fieldName]&FieldFlagReadOnly == 0 {
+			b.fieldFlags[fieldName] += FieldFlagReadOnly
+		}
+	case "insertonly":
+		if b.fieldFlags[fieldName]&FieldFlagInsertOnly == 0 {
+			b.fieldFlags[fieldName] += FieldFlagInsertOnly
+		}
+	case "version":
+		b.setFieldVersion(fieldName, fieldType)
+	case "deleted":
+		if b.fieldFlags[fieldName]&FieldFlagSoftDelete == 0 {
+			b.fieldFlags[fieldName] += FieldFlagSoftDelete
+		}
+	case "pk":
+		// Type of primary key without a value depends on whether the key is composite, which is known after all fields are parsed
+		b.setFieldPrimaryKey(fieldName, fieldType, "")
+	case "index":
+		b.setFieldIndex(fieldName, "")
+	case "jsonb":
+		// Slices (except []byte) can be stored as JSON arrays instead of PostgreSQL arrays
+		if !isJSONType(fieldType) && (fieldType.Kind() != reflect.Slice || fieldType.Elem().Kind() == reflect.Uint8) {
+			b.addWarning(fieldName, fmt.Errorf("%w: jsonb requires a slice, a map or a struct", invalidTagValueError))
+			return
+		}
+		if b.fieldFlags[fieldName]&FieldFlagJSON == 0 {
+			b.fieldFlags[fieldName] += FieldFlagJSON
+		}
+	default:
+		b.addWarning(fieldName, fmt.Errorf("%w: %s", unknownTagOptionError, opt))
 	}
+}
 
-	if opt == "pass" && b.fieldFlags[fieldName]&FieldFlagPassword == 0 {
-		b.fieldFlags[fieldName] += FieldFlagPassword
-		return
+func (b *Builder) setFieldFromTagOptWithVal(key string, val string, fieldName string, fieldType reflect.Type) {
+	switch key {
+	case "type":
+		b.setFieldColumnType(fieldName, fieldType, strings.ToUpper(val))
+	case "pk":
+		b.setFieldPrimaryKey(fieldName, fieldType, strings.ToLower(val))
+	case "fk":
+		b.setFieldForeignKey(fieldName).reference = val
+	case "uniq":
+		// Fields with the same group name make a composite unique constraint
+		_, ok := b.uniqueGroups[val]
+		if !ok {
+			b.uniqueGroupNames = append(b.uniqueGroupNames, val)
+		}
+		b.uniqueGroups[val] = append(b.uniqueGroups[val], fieldName)
+	case "column", "name":
+		if val == "" || strings.Contains(val, `"`) {
+			b.addError(getTagBuilderError(fieldName, b.tagName, fmt.Errorf("%w: %q", columnNameError, val)))
+			return
+		}
+		b.fieldColumnName[fieldName] = val
+	case "enum":
+		values := strings.Split(val, "|")
+		for i, value := range values {
+			values[i] = commentFromTag(value)
+		}
+		b.fieldEnumValues[fieldName] = values
+	case "generated":
+		b.fieldGenerated[fieldName] = val
+		if b.fieldFlags[fieldName]&FieldFlagGenerated == 0 {
+			b.fieldFlags[fieldName] += FieldFlagGenerated
+		}
+	case "comment":
+		b.fieldComment[fieldName] = commentFromTag(val)
+	case "check":
+		b.fieldChecks[fieldName] = val
+	case "index":
+		b.setFieldIndex(fieldName, val)
+	case "using":
+		b.fieldIndexMethod[fieldName] = strings.ToLower(val)
+	case "where":
+		b.fieldIndexWhere[fieldName] = val
+	case "ondelete", "onupdate":
+		action, ok := foreignKeyActions[strings.ToLower(val)]
+		if !ok {
+			b.addError(getTagBuilderError(fieldName, b.tagName, fmt.Errorf("%w: unknown %s action %s", foreignKeyError, key, val)))
+			return
+		}
+
+		if key == "ondelete" {
+			b.setFieldForeignKey(fieldName).onDelete = action
+		} else {
+			b.setFieldForeignKey(fieldName).onUpdate = action
+		}
+	case "prefix":
+		// Prefix is used by structs only, and they are flattened before their fields are parsed
+		b.addWarning(fieldName, fmt.Errorf("%w: prefix requires a struct field", invalidTagValueError))
+	default:
+		b.addWarning(fieldName, fmt.Errorf("%w: %s", unknownTagOptionError, key))
 	}
+}
 
-	if !strings.HasPrefix(opt, "type:") {
+// setFieldPrimaryKey marks the field as a primary key.
+// Primary key can be generated by the database (serial, bigserial, identity, uuid) or set by the application (natural).
+func (b *Builder) setFieldPrimaryKey(fieldName string, fieldType reflect.Type, pkType string) {
+	if b.fieldFlags[fieldName]&FieldFlagPrimaryKey > 0 {
 		return
 	}
 
-	typeArr := strings.Split(opt, ":")
-	typeUpperCase := strings.ToUpper(typeArr[1])
-	if typeUpperCase == "TEXT" || typeUpperCase == "BPCHAR" {
-		b.fieldColumnType[fieldName] = typeUpperCase
+	if pkType != "" {
+		err := validatePrimaryKeyType(pkType, fieldType)
+		if err != nil {
+			b.addError(getTagBuilderError(fieldName, b.tagName, err))
+			return
+		}
+		b.fieldPrimaryKeyType[fieldName] = pkType
+	}
+
+	b.fieldFlags[fieldName] += FieldFlagPrimaryKey
+}
+
+// setFieldForeignKey returns foreign key of the field, creating it if necessary.
+func (b *Builder) setFieldForeignKey(fieldName string) *foreignKey {
+	fk, ok := b.fieldForeignKey[fieldName]
+	if !ok {
+		fk = &foreignKey{}
+		b.fieldForeignKey[fieldName] = fk
+	}
+
+	return fk
+}
+
+// setFieldColumnType overwrites the default column type of the field.
+func (b *Builder) setFieldColumnType(fieldName string, fieldType reflect.Type, typeUpperCase string) {
+	// time.Time can be stored as a timestamp with or without time zone, or as a date
+	if fieldType == timeType {
+		if typeUpperCase == "TIMESTAMPTZ" || typeUpperCase == "TIMESTAMP" || typeUpperCase == "DATE" {
+			b.fieldColumnType[fieldName] = typeUpperCase
+			return
+		}
+		b.addWarning(fieldName, fmt.Errorf("%w: type %s for time field", invalidTagValueError, typeUpperCase))
 		return
 	}
-	m, _ := regexp.MatchString(`^(VARCHAR|CHARACTER VARYING|BPCHAR|CHAR|CHARACTER)\([0-9]+\)$`, typeUpperCase)
-	if m {
+
+	// Exact numbers can be stored in numbers as well as in strings
+	if regexpNumericType.MatchString(typeUpperCase) {
+		if !isNumericKind(fieldType.Kind()) && fieldType.Kind() != reflect.String {
+			b.addWarning(fieldName, fmt.Errorf("%w: type %s for %s field", invalidTagValueError, typeUpperCase, fieldType.Kind()))
+			return
+		}
 		b.fieldColumnType[fieldName] = typeUpperCase
+
+		// String field behaves like a number column in filters
+		if b.fieldFlags[fieldName]&FieldFlagNotString == 0 {
+			b.fieldFlags[fieldName] += FieldFlagNotString
+		}
 		return
 	}
+
+	if fieldType.Kind() == reflect.String {
+		if typeUpperCase == "TEXT" || typeUpperCase == "BPCHAR" {
+			b.fieldColumnType[fieldName] = typeUpperCase
+			return
+		}
+		m, _ := regexp.MatchString(`^(VARCHAR|CHARACTER VARYING|BPCHAR|CHAR|CHARACTER)\([0-9]+\)$`, typeUpperCase)
+		if m {
+			b.fieldColumnType[fieldName] = typeUpperCase
+			return
+		}
+	}
+
+	b.addWarning(fieldName, fmt.Errorf("%w: type %s for %s field", invalidTagValueError, typeUpperCase, fieldType.Kind()))
 }
 
 // Mapping database column type to struct field type
-func (b *Builder) columnDefinitionFromField(fieldName string, fieldType string, isUnique bool) string {
-	// 'ID' is a special field
-	if fieldName == "ID" {
-		return "SERIAL PRIMARY KEY"
+func (b *Builder) columnDefinitionFromField(fieldName string, fieldType reflect.Type, isUnique bool) string {
+	columnType, columnDefault := columnTypeFromFieldType(fieldType)
+	if b.fieldFlags[fieldName]&FieldFlagJSON > 0 {
+		columnType = "JSONB"
+		columnDefault = "'{}'"
+		if fieldType.Kind() == reflect.Slice {
+			columnDefault = "'[]'"
+		}
 	}
 
 	fieldColumnType, ok := b.fieldColumnType[fieldName]
 	if ok && fieldColumnType != "" {
-		definition := fmt.Sprintf("%s NOT NULL DEFAULT ''", fieldColumnType)
-
-		if isUnique {
-			definition += " UNIQUE"
-		}
-
-		return definition
-	}
-
-	definition := ""
-	switch fieldType {
-	case "string":
-		definition = "VARCHAR(255) NOT NULL DEFAULT ''"
-	case "bool":
-		definition = "BOOLEAN NOT NULL DEFAULT false"
-	case "int64":
-		definition = "BIGINT NOT NULL DEFAULT 0"
-	case "int32":
-		definition = "INTEGER NOT NULL DEFAULT 0"
-	case "int16":
-		definition = "SMALLINT NOT NULL DEFAULT 0"
-	case "int8":
-		definition = "SMALLINT NOT NULL DEFAULT 0"
-	case "int":
-		definition = "BIGINT NOT NULL DEFAULT 0"
-	case "uint64":
-		definition = "BIGINT NOT NULL DEFAULT 0"
-	case "uint32":
-		definition = "INTEGER NOT NULL DEFAULT 0"
-	case "uint16":
-		definition = "SMALLINT NOT NULL DEFAULT 0"
-	case "uint8":
-		definition = "SMALLINT NOT NULL DEFAULT 0"
-	case "uint":
-		definition = "BIGINT NOT NULL DEFAULT 0"
-	// TODO: Consider something different
-	default:
-		definition = "VARCHAR(255) NOT NULL DEFAULT ''"
+		columnType = fieldColumnType
+		if columnType == "DATE" {
+			columnDefault = "CURRENT_DATE"
+		}
+		if regexpNumericType.MatchString(columnType) {
+			columnDefault = "0"
+		}
+	}
+
+	// ENUM column defaults to its first value
+	e, isEnum := b.fieldEnum[fieldName]
+	if isEnum {
+		columnType = quoteIdentifier(e.name)
+		columnDefault = QuoteLiteral(e.values[0])
+	}
+
+	// Expression of generated column is added once all the columns are known
+	if b.fieldFlags[fieldName]&FieldFlagGenerated > 0 {
+		return columnType
+	}
+
+	if b.fieldFlags[fieldName]&FieldFlagPrimaryKey > 0 {
+		return primaryKeyDefinition(b.fieldPrimaryKeyType[fieldName], columnType, len(b.primaryKeyFields) > 1)
+	}
+
+	// Default value can be set with the '_val' tag, eg. sql_val
+	valTagValue, hasDefault := b.fieldDefault[fieldName]
+	if hasDefault {
+		var err error
+		columnDefault, err = columnDefaultFromTag(valTagValue, fieldType, columnType, b.fieldFlags[fieldName])
+		if err == nil && isEnum && columnDefault != "NULL" && !e.hasValue(strings.Trim(valTagValue, "'")) {
+			err = invalidDefaultValueError(valTagValue, columnType)
+		}
+		if err != nil {
+			b.addError(getTagBuilderError(fieldName, b.tagName+"_val", err))
+		}
+	}
+
+	// Pointer fields are nullable so they do not get any default value, unless it is set in the tag.
+	// Neither do foreign keys, as the default value would not reference any row.
+	_, isForeignKey := b.fieldForeignKey[fieldName]
+	isNullable := b.fieldFlags[fieldName]&FieldFlagNullable > 0
+
+	definition := columnType
+	if !isNullable {
+		definition += " NOT NULL"
+	}
+	if hasDefault || (!isNullable && !isForeignKey) {
+		definition += " DEFAULT " + columnDefault
 	}
 
 	if isUnique {
@@ -251,8 +733,234 @@ func (b *Builder) columnDefinitionFromField(fieldName string, fieldType string,
 	return definition
 }
 
-func (b *Builder) isFieldModification(name string, typeKind reflect.Kind) bool {
-	return (name == "CreatedAt" || name == "CreatedBy" || name == "ModifiedAt" || name == "ModifiedBy") && typeKind == reflect.Int64
+// primaryKeyDefinition returns a column definition for a primary key.
+// Column that is a part of a composite primary key does not get PRIMARY KEY, as it is added as a table constraint.
+func primaryKeyDefinition(pkType string, columnType string, isComposite bool) string {
+	definition := columnType
+	switch pkType {
+	case primaryKeySerial:
+		definition = "SERIAL"
+	case primaryKeyBigSerial:
+		definition = "BIGSERIAL"
+	case primaryKeyIdentity:
+		// Identity requires an integer column, so unsigned integers stored as NUMERIC get BIGINT
+		definition = columnType + " GENERATED ALWAYS AS IDENTITY"
+		if columnType != "SMALLINT" && columnType != "INTEGER" && columnType != "BIGINT" {
+			definition = "BIGINT GENERATED ALWAYS AS IDENTITY"
+		}
+	case primaryKeyUUID:
+		definition = "UUID"
+	}
+
+	if isComposite {
+		definition += " NOT NULL"
+	} else {
+		definition += " PRIMARY KEY"
+	}
+
+	if pkType == primaryKeyUUID {
+		definition += " DEFAULT gen_random_uuid()"
+	}
+
+	return definition
+}
+
+// isGeneratedPrimaryKey checks if value of the primary key is generated by the database.
+func isGeneratedPrimaryKey(pkType string) bool {
+	switch pkType {
+	case primaryKeySerial, primaryKeyBigSerial, primaryKeyIdentity, primaryKeyUUID:
+		return true
+	default:
+		return false
+	}
+}
+
+// validatePrimaryKeyType checks if the primary key type can be used with the field type.
+func validatePrimaryKeyType(pkType string, fieldType reflect.Type) error {
+	switch pkType {
+	case primaryKeySerial, primaryKeyBigSerial, primaryKeyIdentity:
+		if !isIntegerKind(fieldType.Kind()) {
+			return fmt.Errorf("%w: %s requires an integer field", primaryKeyError, pkType)
+		}
+	case primaryKeyUUID:
+		if fieldType.Kind() != reflect.String {
+			return fmt.Errorf("%w: %s requires a string field", primaryKeyError, pkType)
+		}
+	case primaryKeyNatural:
+		if isJSONType(fieldType) || fieldType.Kind() == reflect.Slice {
+			return fmt.Errorf("%w: %s cannot be a primary key", primaryKeyError, fieldType)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %s", primaryKeyError, pkType)
+	}
+
+	return nil
+}
+
+// columnDefaultFromTag returns a DEFAULT expression from the '_val' tag value.
+// Function calls, eg. now() or gen_random_uuid(), and SQL keywords, eg. CURRENT_TIMESTAMP, are used as they are.
+// Arguments of function calls are limited to numbers, string literals, identifiers and calls without arguments.
+// Any other value is a literal, optionally wrapped in single quotes, that is checked against the column type.
+func columnDefaultFromTag(value string, fieldType reflect.Type, columnType string, fieldFlags int64) (string, error) {
+	if regexpDefaultFunction.MatchString(value) {
+		return value, nil
+	}
+
+	keyword := strings.ToUpper(value)
+	switch keyword {
+	case "CURRENT_TIMESTAMP", "CURRENT_DATE", "CURRENT_TIME", "LOCALTIMESTAMP", "LOCALTIME", "CURRENT_USER", "SESSION_USER":
+		return keyword, nil
+	case "NULL":
+		if fieldFlags&FieldFlagNullable == 0 {
+			return "", invalidDefaultValueError(value, columnType)
+		}
+		return keyword, nil
+	}
+
+	if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+		value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+
+	// JSONB value is checked by its column, as the field type might be a slice
+	if fieldFlags&FieldFlagJSON > 0 {
+		if !json.Valid([]byte(value)) {
+			return "", invalidDefaultValueError(value, columnType)
+		}
+		return QuoteLiteral(value), nil
+	}
+
+	// Exact numbers are checked against column's precision and scale
+	if regexpNumericType.MatchString(columnType) {
+		if !numericValueFitsTag("type:"+columnType, value) || (isIntegerKind(fieldType.Kind()) && !integerFitsColumn(value, fieldType, columnType)) {
+			return "", invalidDefaultValueError(value, columnType)
+		}
+		return value, nil
+	}
+
+	if fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() == reflect.Uint8 {
+		return QuoteLiteral(value), nil
+	}
+
+	if fieldType.Kind() == reflect.String {
+		// Value cannot be longer than the column allows, eg. VARCHAR(10)
+		sizeMatch := regexpStringTypeSize.FindStringSubmatch(columnType)
+		if sizeMatch != nil {
+			size, _ := strconv.Atoi(sizeMatch[1])
+			if utf8.RuneCountInString(value) > size {
+				return "", invalidDefaultValueError(value, columnType)
+			}
+		}
+		return QuoteLiteral(value), nil
+	}
+
+	ok, parsed := valueFromString(fieldType, value)
+	if !ok {
+		return "", invalidDefaultValueError(value, columnType)
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(parsed.(bool)), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !integerFitsColumn(value, fieldType, columnType) {
+			return "", invalidDefaultValueError(value, columnType)
+		}
+		return value, nil
+	case reflect.Float32, reflect.Float64:
+		return value, nil
+	case reflect.Slice:
+		return QuoteLiteral("{" + strings.TrimSuffix(strings.TrimPrefix(value, "{"), "}") + "}"), nil
+	default:
+		return QuoteLiteral(value), nil
+	}
+}
+
+// integerFitsColumn checks if an integer value is in the range of the field type, and of the column type, eg. SMALLINT.
+func integerFitsColumn(value string, fieldType reflect.Type, columnType string) bool {
+	var err error
+	if isUnsignedKind(fieldType.Kind()) {
+		_, err = strconv.ParseUint(value, 10, fieldType.Bits())
+	} else {
+		_, err = strconv.ParseInt(value, 10, fieldType.Bits())
+	}
+	if err != nil {
+		return false
+	}
+
+	bits, ok := integerColumnBits[columnType]
+	if !ok {
+		return true
+	}
+	_, err = strconv.ParseInt(value, 10, bits)
+
+	return err == nil
+}
+
+// columnTypeFromFieldType returns the default column type and its default value for a struct field type
+func columnTypeFromFieldType(fieldType reflect.Type) (string, string) {
+	if fieldType == timeType {
+		return "TIMESTAMPTZ", "now()"
+	}
+
+	// []byte is binary data, and other slices are arrays of their item type
+	if fieldType.Kind() == reflect.Slice {
+		if fieldType.Elem().Kind() == reflect.Uint8 {
+			return "BYTEA", "''"
+		}
+
+		if fieldType.Elem().Kind() == reflect.String {
+			return "TEXT[]", "'{}'"
+		}
+
+		itemType, _ := columnTypeFromFieldType(fieldType.Elem())
+		return itemType + "[]", "'{}'"
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String:
+		return "VARCHAR(255)", "''"
+	case reflect.Bool:
+		return "BOOLEAN", "false"
+	case reflect.Int64:
+		return "BIGINT", "0"
+	case reflect.Int32:
+		return "INTEGER", "0"
+	case reflect.Int16:
+		return "SMALLINT", "0"
+	case reflect.Int8:
+		return "SMALLINT", "0"
+	case reflect.Int:
+		return "BIGINT", "0"
+	// Unsigned integers need a wider type to fit their maximum value
+	case reflect.Uint64:
+		return "NUMERIC(20,0)", "0"
+	case reflect.Uint32:
+		return "BIGINT", "0"
+	case reflect.Uint16:
+		return "INTEGER", "0"
+	case reflect.Uint8:
+		return "SMALLINT", "0"
+	case reflect.Uint:
+		return "NUMERIC(20,0)", "0"
+	case reflect.Float64:
+		return "DOUBLE PRECISION", "0"
+	case reflect.Float32:
+		return "REAL", "0"
+	// TODO: Consider something different
+	default:
+		return "VARCHAR(255)", "''"
+	}
+}
+
+// isColumnField checks if a struct field becomes a table column.
+func isColumnField(field reflect.StructField) bool {
+	// Embedded structs are flattened, and other embedded types are not stored in a JSONB column
+	if field.Anonymous && isJSONType(field.Type) {
+		return false
+	}
+
+	return IsFieldTypeSupported(field.Type)
 }
 
 func (b *Builder) queryOrder(order []string) (string, error) {
@@ -302,29 +1010,105 @@ func (b *Builder) querySet(values map[string]interface{}) (string, int, error) {
 		return "", 0, nil
 	}
 
-	columns := make([]string, 0, len(values))
+	// Columns are sorted by field names, the same way as values in MapInterfaces
+	fieldNames := make([]string, 0, len(values))
 	for value := range values {
-		fieldColumn, ok := b.fieldColumnName[value]
+		fieldNames = append(fieldNames, value)
+	}
+	sort.Strings(fieldNames)
+
+	// Value of version is the expected one, so it does not have a column, but it keeps its placeholder
+	querySet := ""
+	for i, fieldName := range fieldNames {
+		fieldColumn, ok := b.fieldColumnName[fieldName]
 		if !ok {
 			return "", 0, getColumnNameBuilderError("value")
 		}
 
-		columns = append(columns, fieldColumn)
+		_, isAutoValue := b.fieldAutoValue[fieldName]
+		if b.fieldFlags[fieldName]&(FieldFlagGenerated|FieldFlagReadOnly|FieldFlagInsertOnly) > 0 || isAutoValue {
+			return "", 0, fmt.Errorf("%w: %s cannot be updated", fieldNotWritableError, fieldName)
+		}
+
+		if fieldName != b.versionField {
+			querySet += fmt.Sprintf(`,"%s"=$%d`, fieldColumn, i+1)
+		}
 	}
 
-	numColumn := len(columns)
-	if numColumn == 0 {
-		return "", 0, nil
+	// Columns such as ModifiedAt are set by the database on every update, and version is incremented
+	return joinNotEmpty(",", strings.TrimPrefix(querySet, ","), b.autoValueSet(false), b.versionSet(false)), len(fieldNames), nil
+}
+
+// filterColumn returns a quoted column for a field name, which can be a path to a key in JSONB column, eg. Settings.theme.
+// When asText is true, the last key in the path is extracted as text.
+func (b *Builder) filterColumn(name string, asText bool) (string, bool) {
+	fieldColumn, ok := b.fieldColumnName[name]
+	if ok {
+		return fmt.Sprintf(`"%s"`, fieldColumn), true
 	}
 
-	sort.Strings(columns)
+	path := strings.Split(name, ".")
+	if len(path) < 2 || b.fieldFlags[path[0]]&FieldFlagJSON == 0 {
+		return "", false
+	}
 
-	querySet := ""
-	for i := 1; i <= numColumn; i++ {
-		querySet += fmt.Sprintf(`,"%s"=$%d`, columns[i-1], i)
+	fieldColumn = fmt.Sprintf(`"%s"`, b.fieldColumnName[path[0]])
+	for i, key := range path[1:] {
+		if !regexpJSONKey.MatchString(key) {
+			return "", false
+		}
+
+		operator := "->"
+		if asText && i == len(path)-2 {
+			operator = "->>"
+		}
+
+		fieldColumn += fmt.Sprintf("%s'%s'", operator, key)
+	}
+
+	return fieldColumn, true
+}
+
+// reflectGenerated adds expressions to definitions of generated columns, once all the columns are known.
+func (b *Builder) reflectGenerated() {
+	for i, fieldName := range b.fieldNames {
+		expression, ok := b.fieldGenerated[fieldName]
+		if !ok {
+			continue
+		}
+
+		var err error
+		_, hasDefault := b.fieldDefault[fieldName]
+		_, isForeignKey := b.fieldForeignKey[fieldName]
+		if b.fieldFlags[fieldName]&FieldFlagPrimaryKey > 0 || hasDefault || isForeignKey {
+			err = fmt.Errorf("%w: generated column cannot be a primary key, a foreign key or have a default value", generatedColumnError)
+		} else {
+			expression, err = b.resolveFields(expression, "generated column")
+		}
+		if err != nil {
+			b.addError(getTagBuilderError(fieldName, b.tagName, err))
+			continue
+		}
+
+		b.columnDefinitions[i] += fmt.Sprintf(" GENERATED ALWAYS AS (%s) STORED", expression)
 	}
+}
+
+// resolveFields replaces fields in an expression, eg. .Age > 18, with their columns.
+func (b *Builder) resolveFields(expression string, source string) (string, error) {
+	var err error
+	expression = regexpFieldInRaw.ReplaceAllStringFunc(expression, func(fieldInRaw string) string {
+		match := regexpFieldInRaw.FindStringSubmatch(fieldInRaw)
+
+		fieldColumn, ok := b.filterColumn(match[2], true)
+		if !ok && err == nil {
+			err = getColumnNameBuilderError(source)
+		}
+
+		return match[1] + fieldColumn
+	})
 
-	return querySet[1:], numColumn, nil
+	return expression, err
 }
 
 func (b *Builder) queryFilters(filters *Filters, firstValueNum int) (string, error) {
@@ -347,15 +1131,45 @@ func (b *Builder) queryFilters(filters *Filters, firstValueNum int) (string, err
 			continue
 		}
 
-		fieldColumn, ok := b.fieldColumnName[name]
+		// JSONB operators need a JSONB value of the key, others compare its text value
+		op := (*filters)[name].Op
+		fieldColumn, ok := b.filterColumn(name, op != OpContains && op != OpContainedBy && op != OpHasKey)
 		if !ok {
 			return "", getColumnNameBuilderError("filter")
 		}
 
-		if b.fieldFlags[name]&FieldFlagNotString > 0 && ((*filters)[name].Op == OpLike || (*filters)[name].Op == OpMatch) {
-			fieldColumn = fmt.Sprintf(`CAST("%s" AS TEXT)`, fieldColumn)
-		} else {
-			fieldColumn = fmt.Sprintf(`"%s"`, fieldColumn)
+		// Array and JSONB operators need an array or a JSONB column, or a path to a key in JSONB column
+		_, isField := b.fieldColumnName[name]
+		if (op == OpContains || op == OpContainedBy || op == OpOverlap || op == OpHasKey) && isField && b.fieldFlags[name]&(FieldFlagArray|FieldFlagJSON) == 0 {
+			return "", getOperatorBuilderError(name)
+		}
+
+		if b.fieldFlags[name]&FieldFlagNotString > 0 && (op == OpLike || op == OpMatch) {
+			fieldColumn = fmt.Sprintf(`CAST(%s AS TEXT)`, fieldColumn)
+		}
+
+		// ENUM column accepts only its values, and PostgreSQL would fail the whole query otherwise
+		if op == OpEqual || op == OpNotEqual || op == OpAny {
+			if !isNilValue((*filters)[name].Val) {
+				err := b.validateEnumValue(name, (*filters)[name].Val)
+				if err != nil {
+					return "", err
+				}
+			}
+		}
+
+		// nil value does not have a placeholder, it is compared with NULL instead
+		if isNilValue((*filters)[name].Val) {
+			switch (*filters)[name].Op {
+			case OpEqual:
+				queryWhere += fmt.Sprintf(` AND %s IS NULL`, fieldColumn)
+			case OpNotEqual:
+				queryWhere += fmt.Sprintf(` AND %s IS NOT NULL`, fieldColumn)
+			default:
+				return "", getNilValueBuilderError(name)
+			}
+
+			continue
 		}
 
 		switch (*filters)[name].Op {
@@ -375,6 +1189,21 @@ func (b *Builder) queryFilters(filters *Filters, firstValueNum int) (string, err
 			queryWhere += fmt.Sprintf(` AND %s<=$%d`, fieldColumn, valueNum)
 		case OpBit:
 			queryWhere += fmt.Sprintf(` AND %s&$%d>0`, fieldColumn, valueNum)
+		case OpContains:
+			queryWhere += fmt.Sprintf(` AND %s @> $%d`, fieldColumn, valueNum)
+		case OpContainedBy:
+			queryWhere += fmt.Sprintf(` AND %s <@ $%d`, fieldColumn, valueNum)
+		case OpOverlap:
+			queryWhere += fmt.Sprintf(` AND %s && $%d`, fieldColumn, valueNum)
+		case OpHasKey:
+			queryWhere += fmt.Sprintf(` AND %s ? $%d`, fieldColumn, valueNum)
+		case OpAny:
+			// Array column contains the value, or column is equal to any of the values from the array
+			if b.fieldFlags[name]&FieldFlagArray > 0 {
+				queryWhere += fmt.Sprintf(` AND $%d = ANY(%s)`, valueNum, fieldColumn)
+			} else {
+				queryWhere += fmt.Sprintf(` AND %s = ANY($%d)`, fieldColumn, valueNum)
+			}
 		default:
 			queryWhere += fmt.Sprintf(` AND %s=$%d`, fieldColumn, valueNum)
 		}
@@ -410,22 +1239,9 @@ func (b *Builder) queryFilters(filters *Filters, firstValueNum int) (string, err
 
 	queryWhere += "("
 
-	fieldsInRaw := regexpFieldInRaw.FindAllString(rawQuery, -1)
-	alreadyReplaced := map[string]bool{}
-	for _, fieldInRaw := range fieldsInRaw {
-		if alreadyReplaced[fieldInRaw] {
-			continue
-		}
-
-		fieldName := strings.Replace(fieldInRaw, ".", "", 1)
-
-		fieldColumn, ok := b.fieldColumnName[fieldName]
-		if !ok {
-			return "", getColumnNameBuilderError("raw query")
-		}
-
-		rawQuery = strings.ReplaceAll(rawQuery, fieldInRaw, fmt.Sprintf(`"%s"`, fieldColumn))
-		alreadyReplaced[fieldInRaw] = true
+	rawQuery, err := b.resolveFields(rawQuery, "raw query")
+	if err != nil {
+		return "", err
 	}
 
 	numRaw := len((*filters)[Raw].Val.([]interface{}))
@@ -1,7 +1,12 @@
 package pgsqlbuilder
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 )
 
 type TestStruct struct {
@@ -258,3 +263,1498 @@ func TestSQLUpdateQueries(t *testing.T) {
 		t.Fatalf("\nwant %v\ngot  %v", want, got)
 	}
 }
+
+type TestTimeStruct struct {
+	ID        int64
+	Name      string
+	StartsAt  time.Time
+	BirthDate time.Time `sql:"type:date"`
+	LoggedAt  time.Time `sql:"type:timestamp"`
+}
+
+func TestSQLTimeQueries(t *testing.T) {
+	h := New(&TestTimeStruct{}, Options{})
+
+	got := h.CreateTable()
+	want := `CREATE TABLE IF NOT EXISTS "test_time_struct" ("id" SERIAL PRIMARY KEY,"name" VARCHAR(255) NOT NULL DEFAULT '',` +
+		`"starts_at" TIMESTAMPTZ NOT NULL DEFAULT now(),"birth_date" DATE NOT NULL DEFAULT CURRENT_DATE,` +
+		`"logged_at" TIMESTAMP NOT NULL DEFAULT now());`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+
+	got = h.Insert()
+	want = `INSERT INTO "test_time_struct"("name","starts_at","birth_date","logged_at") VALUES ($1,$2,$3,$4) RETURNING "id";`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+
+	got, _ = h.Select(nil, 0, 0, &Filters{
+		"StartsAt":  {Op: OpGreaterOrEqual, Val: time.Now()},
+		"BirthDate": {Op: OpLower, Val: time.Now()},
+	})
+	want = `SELECT "id","name","starts_at","birth_date","logged_at" FROM "test_time_struct" WHERE "birth_date"<$1 AND "starts_at">=$2;`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+}
+
+type TestNullableStruct struct {
+	ID        int64
+	Name      string
+	Nickname  *string `sql:"type:varchar(50)"`
+	Score     *int64
+	Active    *bool
+	DeletedAt *time.Time
+	Email     *string `sql:"uniq"`
+}
+
+func TestSQLNullableQueries(t *testing.T) {
+	h := New(&TestNullableStruct{}, Options{})
+
+	got := h.CreateTable()
+	want := `CREATE TABLE IF NOT EXISTS "test_nullable_struct" ("id" SERIAL PRIMARY KEY,"name" VARCHAR(255) NOT NULL DEFAULT '',` +
+		`"nickname" VARCHAR(50),"score" BIGINT,"active" BOOLEAN,"deleted_at" TIMESTAMPTZ,"email" VARCHAR(255) UNIQUE);`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+
+	filters := &Filters{
+		"DeletedAt": {Op: OpEqual, Val: nil},
+		"Nickname":  {Op: OpNotEqual, Val: (*string)(nil)},
+		"Score":     {Op: OpGreater, Val: 10},
+	}
+	got, _ = h.SelectCount(filters)
+	want = `SELECT COUNT(*) AS cnt FROM "test_nullable_struct" WHERE "deleted_at" IS NULL AND "nickname" IS NOT NULL AND "score">$1;`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+
+	if len(FiltersInterfaces(filters)) != 1 {
+		t.Fatalf("nil filter values must not be passed as query arguments")
+	}
+
+	_, err := h.SelectCount(&Filters{"Score": {Op: OpGreater, Val: nil}})
+	if err == nil {
+		t.Fatalf("nil value with OpGreater should return an error")
+	}
+}
+
+type TestNumberStruct struct {
+	ID      int64
+	Ratio   float32
+	Score   float64
+	Price   float64 `sql:"type:numeric(10,2)"`
+	Balance string  `sql:"type:numeric(12,2)"`
+	Weight  *float64
+}
+
+func TestSQLNumberQueries(t *testing.T) {
+	h := New(&TestNumberStruct{}, Options{})
+
+	got := h.CreateTable()
+	want := `CREATE TABLE IF NOT EXISTS "test_number_struct" ("id" SERIAL PRIMARY KEY,"ratio" REAL NOT NULL DEFAULT 0,` +
+		`"score" DOUBLE PRECISION NOT NULL DEFAULT 0,"price" NUMERIC(10,2) NOT NULL DEFAULT 0,` +
+		`"balance" NUMERIC(12,2) NOT NULL DEFAULT 0,"weight" DOUBLE PRECISION);`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+
+	got, _ = h.SelectCount(&Filters{
+		"Balance": {Op: OpGreaterOrEqual, Val: "100.50"},
+		"Score":   {Op: OpLower, Val: 0.5},
+		"Price":   {Op: OpLike, Val: "9%"},
+	})
+	want = `SELECT COUNT(*) AS cnt FROM "test_number_struct" WHERE "balance">=$1 AND CAST("price" AS TEXT) LIKE $2 AND "score"<$3;`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+}
+
+type TestArrayStruct struct {
+	ID          int64
+	Tags        []string
+	Permissions []int64
+	Levels      []int32
+	Checksum    []byte
+}
+
+func TestSQLArrayQueries(t *testing.T) {
+	h := New(&TestArrayStruct{}, Options{})
+
+	got := h.CreateTable()
+	want := `CREATE TABLE IF NOT EXISTS "test_array_struct" ("id" SERIAL PRIMARY KEY,"tags" TEXT[] NOT NULL DEFAULT '{}',` +
+		`"permissions" BIGINT[] NOT NULL DEFAULT '{}',"levels" INTEGER[] NOT NULL DEFAULT '{}',"checksum" BYTEA NOT NULL DEFAULT '');`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+
+	got, _ = h.Select(nil, 0, 0, &Filters{
+		"Tags":        {Op: OpContains, Val: []string{"a", "b"}},
+		"Permissions": {Op: OpOverlap, Val: []int64{1, 2}},
+		"Levels":      {Op: OpAny, Val: 3},
+		"ID":          {Op: OpAny, Val: []int64{4, 5, 6}},
+		"Checksum":    {Op: OpEqual, Val: []byte{0}},
+	})
+	want = `SELECT "id","tags","permissions","levels","checksum" FROM "test_array_struct" WHERE "checksum"=$1 AND "id" = ANY($2) AND` +
+		` $3 = ANY("levels") AND "permissions" && $4 AND "tags" @> $5;`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+
+	got, _ = h.SelectCount(&Filters{
+		"Tags": {Op: OpContainedBy, Val: []string{"a", "b", "c"}},
+	})
+	want = `SELECT COUNT(*) AS cnt FROM "test_array_struct" WHERE "tags" <@ $1;`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+
+	for _, op := range []int{OpContains, OpContainedBy, OpOverlap, OpHasKey} {
+		_, err := h.SelectCount(&Filters{"ID": {Op: op, Val: []int64{1}}})
+		if !errors.Is(err, columnOperatorError) {
+			t.Fatalf("want column operator error, got %v", err)
+		}
+	}
+}
+
+type TestJSONSettings struct {
+	Theme string
+}
+
+type TestJSONStruct struct {
+	ID       int64
+	Settings TestJSONSettings
+	Meta     map[string]interface{}
+	Items    []TestJSONSettings
+	Labels   []string `sql:"jsonb"`
+	Extra    *TestJSONSettings
+}
+
+func TestSQLJSONQueries(t *testing.T) {
+	h := New(&TestJSONStruct{}, Options{})
+
+	got := h.CreateTable()
+	want := `CREATE TABLE IF NOT EXISTS "test_j_s_o_n_struct" ("id" SERIAL PRIMARY KEY,"settings" JSONB NOT NULL DEFAULT '{}',` +
+		`"meta" JSONB NOT NULL DEFAULT '{}',"items" JSONB NOT NULL DEFAULT '[]',"labels" JSONB NOT NULL DEFAULT '[]',"extra" JSONB);`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+
+	got, _ = h.SelectCount(&Filters{
+		"Settings.theme":   {Op: OpEqual, Val: "dark"},
+		"Meta":             {Op: OpHasKey, Val: "source"},
+		"Meta.address.zip": {Op: OpLike, Val: "11%"},
+		"Labels":           {Op: OpContains, Val: JSONB{Val: []string{"a"}}},
+		"Meta.tags":        {Op: OpContains, Val: `["b"]`},
+	})
+	want = `SELECT COUNT(*) AS cnt FROM "test_j_s_o_n_struct" WHERE "labels" @> $1 AND "meta" ? $2 AND "meta"->'address'->>'zip' LIKE $3 AND` +
+		` "meta"->'tags' @> $4 AND "settings"->>'theme'=$5;`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+
+	got, _ = h.SelectCount(&Filters{
+		Raw: {Val: []interface{}{".Settings.theme=? OR .ID>?", "dark", 1}},
+	})
+	want = `SELECT COUNT(*) AS cnt FROM "test_j_s_o_n_struct" WHERE ("settings"->>'theme'=$1 OR "id">$2);`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+
+	interfaces := FiltersInterfaces(&Filters{
+		"Meta":     {Op: OpContains, Val: map[string]string{"source": "api"}},
+		"Settings": {Op: OpContains, Val: TestJSONSettings{Theme: "dark"}},
+		"Extra":    {Op: OpContains, Val: &TestJSONSettings{Theme: "light"}},
+		"ID":       {Op: OpEqual, Val: 1},
+		"Updated":  {Op: OpEqual, Val: time.Time{}},
+	})
+	values := make([]string, 0, len(interfaces))
+	for _, i := range interfaces {
+		v, ok := i.(JSONB)
+		if !ok {
+			values = append(values, fmt.Sprintf("%T", i))
+			continue
+		}
+		value, err := v.Value()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		values = append(values, fmt.Sprintf("%v", value))
+	}
+	got = strings.Join(values, " ")
+	want = `{"Theme":"light"} int {"source":"api"} {"Theme":"dark"} time.Time`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+
+	_, err := h.SelectCount(&Filters{"Settings.th'eme": {Op: OpEqual, Val: "dark"}})
+	if err == nil {
+		t.Fatalf("invalid JSON key should return an error")
+	}
+
+	_, err = h.SelectCount(&Filters{"ID.value": {Op: OpEqual, Val: 1}})
+	if err == nil {
+		t.Fatalf("path in a non-JSONB field should return an error")
+	}
+}
+
+type TestJSONValuerStruct struct {
+	ID    int64
+	Name  sql.NullString
+	Count *sql.NullInt64
+	Meta  map[string]string
+}
+
+func TestSQLJSONValuerQueries(t *testing.T) {
+	h := New(&TestJSONValuerStruct{}, Options{})
+	want := `CREATE TABLE IF NOT EXISTS "test_j_s_o_n_valuer_struct" ("id" SERIAL PRIMARY KEY,"meta" JSONB NOT NULL DEFAULT '{}');`
+	if h.CreateTable() != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, h.CreateTable())
+	}
+
+	if len(h.Warnings()) != 2 || !errors.Is(h.Warnings()[0], unsupportedFieldError) {
+		t.Fatalf("want 2 unsupported field warnings, got %v", h.Warnings())
+	}
+}
+
+type TestDefaultStruct struct {
+	ID       int64
+	Name     string            `sql_val:"O'Reilly"`
+	Code     string            `sql:"type:varchar(5)" sql_val:"'A\\B'"`
+	Age      int               `sql_val:"18"`
+	Active   bool              `sql_val:"TRUE"`
+	Price    float64           `sql:"type:numeric(6,2)" sql_val:"9.99"`
+	Token    string            `sql_val:"gen_random_uuid()"`
+	StartsAt time.Time         `sql_val:"2024-01-01T00:00:00Z"`
+	EndsAt   *time.Time        `sql_val:"CURRENT_TIMESTAMP"`
+	Note     *string           `sql_val:"null"`
+	Tags     []string          `sql_val:"a,b"`
+	Settings map[string]string `sql_val:"{\"theme\":\"dark\"}"`
+}
+
+func TestSQLDefaultValues(t *testing.T) {
+	h := New(&TestDefaultStruct{}, Options{})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	got := h.CreateTable()
+	want := `CREATE TABLE IF NOT EXISTS "test_default_struct" ("id" SERIAL PRIMARY KEY,"name" VARCHAR(255) NOT NULL DEFAULT 'O''Reilly',` +
+		`"code" VARCHAR(5) NOT NULL DEFAULT E'A\\B',"age" BIGINT NOT NULL DEFAULT 18,"active" BOOLEAN NOT NULL DEFAULT true,` +
+		`"price" NUMERIC(6,2) NOT NULL DEFAULT 9.99,"token" VARCHAR(255) NOT NULL DEFAULT gen_random_uuid(),` +
+		`"starts_at" TIMESTAMPTZ NOT NULL DEFAULT '2024-01-01T00:00:00Z',"ends_at" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,` +
+		`"note" VARCHAR(255) DEFAULT NULL,"tags" TEXT[] NOT NULL DEFAULT '{a,b}',"settings" JSONB NOT NULL DEFAULT '{"theme":"dark"}');`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+}
+
+type TestInvalidDefaultStruct struct {
+	ID  int64
+	Age int `sql_val:"eighteen"`
+}
+
+type TestTooLongDefaultStruct struct {
+	ID   int64
+	Code string `sql:"type:char(2)" sql_val:"ABC"`
+}
+
+type TestNullDefaultStruct struct {
+	ID   int64
+	Name string `sql_val:"NULL"`
+}
+
+type TestOutOfRangeDefaultStruct struct {
+	ID    int64
+	Level int8 `sql_val:"300"`
+}
+
+type TestNegativeDefaultStruct struct {
+	ID    int64
+	Count uint64 `sql_val:"-1"`
+}
+
+type TestFunctionDefaultStruct struct {
+	ID      int64
+	Day     time.Time `sql_val:"date_trunc('day', now())"`
+	Counter int64     `sql_val:"nextval('counter_seq')"`
+	Note    string    `sql_val:"lower('a') || current_user()"`
+}
+
+func TestSQLFunctionDefaultValues(t *testing.T) {
+	h := New(&TestFunctionDefaultStruct{}, Options{})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	want := `CREATE TABLE IF NOT EXISTS "test_function_default_struct" ("id" SERIAL PRIMARY KEY,"day" TIMESTAMPTZ NOT NULL DEFAULT date_trunc('day', now()),` +
+		`"counter" BIGINT NOT NULL DEFAULT nextval('counter_seq'),"note" VARCHAR(255) NOT NULL DEFAULT 'lower(''a'') || current_user()');`
+	if h.CreateTable() != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, h.CreateTable())
+	}
+}
+
+func TestSQLInvalidDefaultValues(t *testing.T) {
+	for _, obj := range []interface{}{&TestInvalidDefaultStruct{}, &TestTooLongDefaultStruct{}, &TestNullDefaultStruct{},
+		&TestOutOfRangeDefaultStruct{}, &TestNegativeDefaultStruct{}} {
+		h := New(obj, Options{})
+		if !errors.Is(h.Err(), defaultValueError) {
+			t.Fatalf("want invalid default value error for %T, got %v", obj, h.Err())
+		}
+	}
+}
+
+type TestKeyStruct struct {
+	Key  string `sql:"pk:uuid"`
+	Name string
+	Age  int
+}
+
+type TestIdentityStruct struct {
+	ID   int64 `sql:"pk:identity"`
+	Name string
+}
+
+type TestStringIDStruct struct {
+	ID   string
+	Name string
+}
+
+type TestNaturalKeyStruct struct {
+	Code string `sql:"pk type:char(3)"`
+	Name string
+}
+
+type Product struct {
+	ID   int64 `sql:"pk:bigserial"`
+	Name string
+}
+
+func TestSQLPrimaryKeyQueries(t *testing.T) {
+	h := New(&TestKeyStruct{}, Options{})
+
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{h.CreateTable(), `CREATE TABLE IF NOT EXISTS "test_key_struct" ("key" UUID PRIMARY KEY DEFAULT gen_random_uuid(),` +
+			`"name" VARCHAR(255) NOT NULL DEFAULT '',"age" BIGINT NOT NULL DEFAULT 0);`},
+		{h.Insert(), `INSERT INTO "test_key_struct"("name","age") VALUES ($1,$2) RETURNING "key";`},
+		{h.UpdateByID(), `UPDATE "test_key_struct" SET "name"=$1,"age"=$2 WHERE "key" = $3;`},
+		{h.SelectByID(), `SELECT "key","name","age" FROM "test_key_struct" WHERE "key" = $1;`},
+		{h.DeleteByID(), `DELETE FROM "test_key_struct" WHERE "key" = $1;`},
+		{h.InsertOnConflictUpdate(), `INSERT INTO "test_key_struct"("key","name","age") VALUES ($1,$2,$3) ` +
+			`ON CONFLICT ("key") DO UPDATE SET "name"=$4,"age"=$5 RETURNING "key";`},
+	}
+
+	h = New(&TestIdentityStruct{}, Options{})
+	tests = append(tests, []struct {
+		got  string
+		want string
+	}{
+		{h.CreateTable(), `CREATE TABLE IF NOT EXISTS "test_identity_struct" ("id" BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,` +
+			`"name" VARCHAR(255) NOT NULL DEFAULT '');`},
+		{h.InsertOnConflictUpdate(), `INSERT INTO "test_identity_struct"("id","name") OVERRIDING SYSTEM VALUE VALUES ($1,$2) ` +
+			`ON CONFLICT ("id") DO UPDATE SET "name"=$3 RETURNING "id";`},
+	}...)
+
+	h = New(&TestStringIDStruct{}, Options{})
+	tests = append(tests, []struct {
+		got  string
+		want string
+	}{
+		{h.CreateTable(), `CREATE TABLE IF NOT EXISTS "test_string_id_struct" ("id" VARCHAR(255) PRIMARY KEY,"name" VARCHAR(255) NOT NULL DEFAULT '');`},
+		{h.Insert(), `INSERT INTO "test_string_id_struct"("id","name") VALUES ($1,$2) RETURNING "id";`},
+	}...)
+
+	h = New(&TestNaturalKeyStruct{}, Options{})
+	tests = append(tests, []struct {
+		got  string
+		want string
+	}{
+		{h.CreateTable(), `CREATE TABLE IF NOT EXISTS "test_natural_key_struct" ("code" CHAR(3) PRIMARY KEY,"name" VARCHAR(255) NOT NULL DEFAULT '');`},
+		{h.Insert(), `INSERT INTO "test_natural_key_struct"("code","name") VALUES ($1,$2) RETURNING "code";`},
+	}...)
+
+	h = New(&Product{}, Options{PrefixPrimaryKey: true})
+	deleteReturningID, _ := h.DeleteReturningID(&Filters{"Name": {Op: OpEqual, Val: "Sock"}})
+	tests = append(tests, []struct {
+		got  string
+		want string
+	}{
+		{h.CreateTable(), `CREATE TABLE IF NOT EXISTS "product" ("product_id" BIGSERIAL PRIMARY KEY,"name" VARCHAR(255) NOT NULL DEFAULT '');`},
+		{h.SelectByID(), `SELECT "product_id","name" FROM "product" WHERE "product_id" = $1;`},
+		{deleteReturningID, `DELETE FROM "product" WHERE "name"=$1 RETURNING "product_id";`},
+	}...)
+
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Fatalf("\nwant %v\ngot  %v", test.want, test.got)
+		}
+	}
+}
+
+type TestInvalidPrimaryKeyStruct struct {
+	ID   string `sql:"pk:serial"`
+	Name string
+}
+
+type TestSingleFieldStruct struct {
+	ID int64
+}
+
+func TestSQLPrimaryKeyErrors(t *testing.T) {
+	h := New(&TestInvalidPrimaryKeyStruct{}, Options{})
+	if !errors.Is(h.Err(), primaryKeyError) {
+		t.Fatalf("want primary key error, got %v", h.Err())
+	}
+
+	h = New(&TestSingleFieldStruct{}, Options{})
+	got := h.Insert()
+	want := `INSERT INTO "test_single_field_struct" DEFAULT VALUES RETURNING "id";`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+}
+
+type TestMembershipStruct struct {
+	UserID  int64 `sql:"pk"`
+	GroupID int64 `sql:"pk"`
+	Role    string
+}
+
+type TestTagLinkStruct struct {
+	PostID int64  `sql:"pk"`
+	Tag    string `sql:"pk type:varchar(50)"`
+}
+
+func TestSQLCompositePrimaryKeyQueries(t *testing.T) {
+	h := New(&TestMembershipStruct{}, Options{})
+
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{h.CreateTable(), `CREATE TABLE IF NOT EXISTS "test_membership_struct" ("user_id" BIGINT NOT NULL,"group_id" BIGINT NOT NULL,` +
+			`"role" VARCHAR(255) NOT NULL DEFAULT '',PRIMARY KEY ("user_id","group_id"));`},
+		{h.Insert(), `INSERT INTO "test_membership_struct"("user_id","group_id","role") VALUES ($1,$2,$3) RETURNING "user_id","group_id";`},
+		{h.SelectByPK(), `SELECT "user_id","group_id","role" FROM "test_membership_struct" WHERE "user_id" = $1 AND "group_id" = $2;`},
+		{h.UpdateByPK(), `UPDATE "test_membership_struct" SET "role"=$1 WHERE "user_id" = $2 AND "group_id" = $3;`},
+		{h.DeleteByPK(), `DELETE FROM "test_membership_struct" WHERE "user_id" = $1 AND "group_id" = $2;`},
+		{h.InsertOnConflictUpdate(), `INSERT INTO "test_membership_struct"("user_id","group_id","role") VALUES ($1,$2,$3) ` +
+			`ON CONFLICT ("user_id","group_id") DO UPDATE SET "role"=$4 RETURNING "user_id","group_id";`},
+	}
+
+	h = New(&TestTagLinkStruct{}, Options{})
+	tests = append(tests, []struct {
+		got  string
+		want string
+	}{
+		{h.CreateTable(), `CREATE TABLE IF NOT EXISTS "test_tag_link_struct" ("post_id" BIGINT NOT NULL,"tag" VARCHAR(50) NOT NULL,` +
+			`PRIMARY KEY ("post_id","tag"));`},
+		{h.InsertOnConflictUpdate(), `INSERT INTO "test_tag_link_struct"("post_id","tag") VALUES ($1,$2) ` +
+			`ON CONFLICT ("post_id","tag") DO NOTHING RETURNING "post_id","tag";`},
+	}...)
+
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Fatalf("\nwant %v\ngot  %v", test.want, test.got)
+		}
+	}
+}
+
+type TestFKUser struct {
+	ID   int64 `sql:"pk:bigserial"`
+	Name string
+}
+
+type TestFKOrder struct {
+	ID         int64
+	UserID     int64  `sql:"fk:User ondelete:cascade"`
+	ReviewerID *int64 `sql:"fk:TestFKUser.id ondelete:set_null onupdate:cascade"`
+	Currency   string `sql:"fk:currency"`
+}
+
+type TestFKInvalidStruct struct {
+	ID     int64
+	UserID int64 `sql:"fk:user.id ondelete:setnull"`
+}
+
+func TestSQLForeignKeyQueries(t *testing.T) {
+	userBuilder := New(&TestFKUser{}, Options{TableNamePrefix: "app_", PrefixPrimaryKey: true})
+
+	h := New(&TestFKOrder{}, Options{References: map[string]*Builder{"User": userBuilder}})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	got := h.CreateTable()
+	want := `CREATE TABLE IF NOT EXISTS "test_f_k_order" ("id" SERIAL PRIMARY KEY,` +
+		`"user_id" BIGINT NOT NULL REFERENCES "app_test_f_k_user"("test_f_k_user_id") ON DELETE CASCADE,` +
+		`"reviewer_id" BIGINT REFERENCES "TestFKUser"("id") ON DELETE SET NULL ON UPDATE CASCADE,` +
+		`"currency" VARCHAR(255) NOT NULL REFERENCES "currency");`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+
+	h = New(&TestFKInvalidStruct{}, Options{})
+	if !errors.Is(h.Err(), foreignKeyError) {
+		t.Fatalf("want foreign key error, got %v", h.Err())
+	}
+}
+
+type TestIndexStruct struct {
+	ID        int64
+	Email     string            `sql:"index"`
+	TenantID  int64             `sql:"index:tenant_created"`
+	CreatedAt time.Time         `sql:"index:tenant_created"`
+	LoggedAt  time.Time         `sql:"index using:brin"`
+	Tags      []string          `sql:"index using:gin"`
+	Active    bool              `sql:"index:active_email where:(.Active = true AND .Price > 0.5)"`
+	Price     float64           `sql:"index:active_email"`
+	Settings  map[string]string `sql:"index using:gin"`
+}
+
+func TestSQLIndexQueries(t *testing.T) {
+	h := New(&TestIndexStruct{}, Options{})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	got := strings.Join(h.CreateIndexes(), "\n")
+	want := `CREATE INDEX IF NOT EXISTS "test_index_struct_email_idx" ON "test_index_struct" ("email");` + "\n" +
+		`CREATE INDEX IF NOT EXISTS "test_index_struct_tenant_created_idx" ON "test_index_struct" ("tenant_id","created_at");` + "\n" +
+		`CREATE INDEX IF NOT EXISTS "test_index_struct_logged_at_idx" ON "test_index_struct" USING brin ("logged_at");` + "\n" +
+		`CREATE INDEX IF NOT EXISTS "test_index_struct_tags_idx" ON "test_index_struct" USING gin ("tags");` + "\n" +
+		`CREATE INDEX IF NOT EXISTS "test_index_struct_active_email_idx" ON "test_index_struct" ("active","price") WHERE ("active" = true AND "price" > 0.5);` + "\n" +
+		`CREATE INDEX IF NOT EXISTS "test_index_struct_settings_idx" ON "test_index_struct" USING gin ("settings");`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+
+	got = strings.Join(h.DropIndexes(), "\n")
+	want = `DROP INDEX IF EXISTS "test_index_struct_email_idx";` + "\n" +
+		`DROP INDEX IF EXISTS "test_index_struct_tenant_created_idx";` + "\n" +
+		`DROP INDEX IF EXISTS "test_index_struct_logged_at_idx";` + "\n" +
+		`DROP INDEX IF EXISTS "test_index_struct_tags_idx";` + "\n" +
+		`DROP INDEX IF EXISTS "test_index_struct_active_email_idx";` + "\n" +
+		`DROP INDEX IF EXISTS "test_index_struct_settings_idx";`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+}
+
+type TestLongIndexNameStruct struct {
+	ID              int64
+	CustomerAccount int64 `sql:"index:customer_account_reference_lookup_first"`
+	CustomerRegion  int64 `sql:"index:customer_account_reference_lookup_second"`
+}
+
+func TestSQLLongIndexNames(t *testing.T) {
+	h := New(&TestLongIndexNameStruct{}, Options{})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	names := make([]string, 0, 2)
+	for _, query := range h.DropIndexes() {
+		name := strings.TrimSuffix(strings.TrimPrefix(query, `DROP INDEX IF EXISTS "`), `";`)
+		if len(name) > 63 || !strings.HasPrefix(name, "test_long_index_name_struct_customer_account_reference_") {
+			t.Fatalf("want index name truncated to 63 bytes, got %v", name)
+		}
+		names = append(names, name)
+	}
+	if len(names) != 2 || names[0] == names[1] {
+		t.Fatalf("want 2 different index names, got %v", names)
+	}
+
+	if !strings.HasPrefix(h.CreateIndexes()[0], `CREATE INDEX IF NOT EXISTS "`+names[0]+`" ON`) {
+		t.Fatalf("want index created with name %v, got %v", names[0], h.CreateIndexes()[0])
+	}
+}
+
+type TestInvalidIndexStruct struct {
+	ID   int64
+	Name string `sql:"index using:fulltext"`
+}
+
+func TestSQLIndexErrors(t *testing.T) {
+	h := New(&TestInvalidIndexStruct{}, Options{})
+	if !errors.Is(h.Err(), indexError) {
+		t.Fatalf("want index error, got %v", h.Err())
+	}
+}
+
+type TestUniqueStruct struct {
+	ID       int64
+	TenantID int64  `sql:"uniq:tenant_code"`
+	Code     string `sql:"uniq:tenant_code"`
+	Email    string `sql:"uniq"`
+	Name     string
+}
+
+func TestSQLUniqueGroupQueries(t *testing.T) {
+	h := New(&TestUniqueStruct{}, Options{})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	got := h.CreateTable()
+	want := `CREATE TABLE IF NOT EXISTS "test_unique_struct" ("id" SERIAL PRIMARY KEY,"tenant_id" BIGINT NOT NULL DEFAULT 0,"code" VARCHAR(255) NOT NULL DEFAULT '',"email" VARCHAR(255) NOT NULL DEFAULT '' UNIQUE,"name" VARCHAR(255) NOT NULL DEFAULT '',CONSTRAINT "test_unique_struct_tenant_code_key" UNIQUE ("tenant_id","code"));`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+
+	tests := []struct {
+		target string
+		action ConflictAction
+		want   string
+	}{
+		{"tenant_code", ConflictAction{Op: ConflictDoNothing}, `INSERT INTO "test_unique_struct"("tenant_id","code","email","name") VALUES ($1,$2,$3,$4) ON CONFLICT ("tenant_id","code") DO NOTHING RETURNING "id";`},
+		{"tenant_code", ConflictAction{Op: ConflictDoUpdate}, `INSERT INTO "test_unique_struct"("tenant_id","code","email","name") VALUES ($1,$2,$3,$4) ON CONFLICT ("tenant_id","code") DO UPDATE SET "email"=EXCLUDED."email","name"=EXCLUDED."name" RETURNING "id";`},
+		{"Email", ConflictAction{Op: ConflictDoUpdate, Fields: []string{"Name"}}, `INSERT INTO "test_unique_struct"("tenant_id","code","email","name") VALUES ($1,$2,$3,$4) ON CONFLICT ("email") DO UPDATE SET "name"=EXCLUDED."name" RETURNING "id";`},
+	}
+	for _, test := range tests {
+		got, err := h.InsertOnConflict(test.target, test.action)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != test.want {
+			t.Fatalf("\nwant %v\ngot  %v", test.want, got)
+		}
+	}
+
+	_, err := h.InsertOnConflict("Name", ConflictAction{Op: ConflictDoNothing})
+	if !errors.Is(err, conflictTargetError) {
+		t.Fatalf("want conflict target error, got %v", err)
+	}
+
+	_, err = h.InsertOnConflict("Email", ConflictAction{Op: ConflictDoUpdate, Fields: []string{"ID"}})
+	if !errors.Is(err, conflictActionError) {
+		t.Fatalf("want conflict action error, got %v", err)
+	}
+
+	groups := h.UniqueGroups()
+	groups["tenant_code"][0] = "Name"
+	delete(groups, "tenant_code")
+	if strings.Join(h.UniqueGroups()["tenant_code"], ",") != "TenantID,Code" {
+		t.Fatalf("want unique groups unchanged, got %v", h.UniqueGroups())
+	}
+}
+
+type TestLongUniqueGroupStruct struct {
+	ID              int64
+	CustomerAccount int64 `sql:"uniq:customer_account_reference_region_lookup"`
+	CustomerRegion  int64 `sql:"uniq:customer_account_reference_region_lookup"`
+}
+
+func TestSQLLongUniqueGroupNames(t *testing.T) {
+	h := New(&TestLongUniqueGroupStruct{}, Options{})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	got := h.CreateTable()
+	start := strings.Index(got, `CONSTRAINT "`) + len(`CONSTRAINT "`)
+	name := got[start : start+strings.Index(got[start:], `"`)]
+	if len(name) > 63 || !strings.HasPrefix(name, "test_long_unique_group_struct_customer_account_") {
+		t.Fatalf("want unique constraint name truncated to 63 bytes, got %v", name)
+	}
+	if !strings.HasSuffix(got, `CONSTRAINT "`+name+`" UNIQUE ("customer_account","customer_region"));`) {
+		t.Fatalf("want unique constraint with name %v, got %v", name, got)
+	}
+}
+
+type TestCheckStruct struct {
+	ID       int64
+	Quantity uint16
+	Stock    *uint64
+	Price    float64 `sql:"check:>=0"`
+	Cost     float64 `sql:"check:(.Price >= .Cost)"`
+	Discount uint8   `sql:"check:<=100"`
+}
+
+func TestSQLCheckQueries(t *testing.T) {
+	h := New(&TestCheckStruct{}, Options{})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	got := h.CreateTable()
+	want := `CREATE TABLE IF NOT EXISTS "test_check_struct" ("id" SERIAL PRIMARY KEY,"quantity" INTEGER NOT NULL DEFAULT 0,"stock" NUMERIC(20,0),"price" DOUBLE PRECISION NOT NULL DEFAULT 0,"cost" DOUBLE PRECISION NOT NULL DEFAULT 0,"discount" SMALLINT NOT NULL DEFAULT 0,` +
+		`CONSTRAINT "test_check_struct_quantity_check" CHECK ("quantity" >= 0),CONSTRAINT "test_check_struct_stock_check" CHECK ("stock" >= 0 AND "stock" <= 18446744073709551615),CONSTRAINT "test_check_struct_price_check" CHECK ("price" >= 0),` +
+		`CONSTRAINT "test_check_struct_cost_check" CHECK (("price" >= "cost")),CONSTRAINT "test_check_struct_discount_check" CHECK ("discount" >= 0),CONSTRAINT "test_check_struct_discount_check1" CHECK ("discount" <= 100));`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+}
+
+type TestUnsignedIdentityStruct struct {
+	ID   uint64 `sql:"pk:identity"`
+	Size uint
+}
+
+func TestSQLUnsignedIdentityQueries(t *testing.T) {
+	h := New(&TestUnsignedIdentityStruct{}, Options{})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	want := `CREATE TABLE IF NOT EXISTS "test_unsigned_identity_struct" ("id" BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,"size" NUMERIC(20,0) NOT NULL DEFAULT 0,` +
+		`CONSTRAINT "test_unsigned_identity_struct_size_check" CHECK ("size" >= 0 AND "size" <= 18446744073709551615));`
+	if h.CreateTable() != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, h.CreateTable())
+	}
+}
+
+type TestLongCheckNameStruct struct {
+	ID                                   int64
+	CustomerAccountReferenceBalanceLimit uint16 `sql:"check:<=1000"`
+}
+
+func TestSQLLongCheckNames(t *testing.T) {
+	h := New(&TestLongCheckNameStruct{}, Options{})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	if len(h.checks) != 2 || h.checks[0].name == h.checks[1].name {
+		t.Fatalf("want 2 different check names, got %v", h.checks)
+	}
+	for _, c := range h.checks {
+		if len(c.name) > 63 || !strings.HasPrefix(c.name, "test_long_check_name_struct_customer_account_reference_") {
+			t.Fatalf("want check name truncated to 63 bytes, got %v", c.name)
+		}
+		if !strings.Contains(h.CreateTable(), `CONSTRAINT "`+c.name+`" CHECK`) {
+			t.Fatalf("want check created with name %v, got %v", c.name, h.CreateTable())
+		}
+	}
+}
+
+type TestInvalidCheckStruct struct {
+	ID    int64
+	Price float64 `sql:"check:(.Amount > 0)"`
+}
+
+func TestSQLCheckErrors(t *testing.T) {
+	h := New(&TestInvalidCheckStruct{}, Options{})
+	if !errors.Is(h.Err(), fieldNameNotFoundError) {
+		t.Fatalf("want field name not found error, got %v", h.Err())
+	}
+}
+
+type TestColumnStruct struct {
+	ID         int64  `sql:"column:legacy_id"`
+	HTTPServer string `sql:"column:http_server"`
+	Alias      string `sql:"name:a_alias"`
+	Zone       string `sql:"index column:b_zone"`
+}
+
+func TestSQLColumnNameQueries(t *testing.T) {
+	h := New(&TestColumnStruct{}, Options{PrefixPrimaryKey: true})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	got, _ := h.Update(map[string]interface{}{"Zone": "eu", "Alias": "x"}, &Filters{
+		"HTTPServer": {Op: OpEqual, Val: "nginx"},
+		Raw:          {Op: OpAND, Val: []interface{}{".HTTPServer != '' OR .ID > ?", 0}},
+	})
+	tests := [][2]string{
+		{h.CreateTable(), `CREATE TABLE IF NOT EXISTS "test_column_struct" ("legacy_id" SERIAL PRIMARY KEY,"http_server" VARCHAR(255) NOT NULL DEFAULT '',"a_alias" VARCHAR(255) NOT NULL DEFAULT '',"b_zone" VARCHAR(255) NOT NULL DEFAULT '');`},
+		{h.SelectByID(), `SELECT "legacy_id","http_server","a_alias","b_zone" FROM "test_column_struct" WHERE "legacy_id" = $1;`},
+		{got, `UPDATE "test_column_struct" SET "a_alias"=$1,"b_zone"=$2 WHERE ("http_server"=$3) AND ("http_server" != '' OR "legacy_id" > $4);`},
+		{strings.Join(h.CreateIndexes(), ""), `CREATE INDEX IF NOT EXISTS "test_column_struct_b_zone_idx" ON "test_column_struct" ("b_zone");`},
+		{h.DatabaseColumnToFieldName("http_server"), "HTTPServer"},
+	}
+	for _, test := range tests {
+		if test[0] != test[1] {
+			t.Fatalf("\nwant %v\ngot  %v", test[1], test[0])
+		}
+	}
+}
+
+type TestDuplicateColumnStruct struct {
+	ID   int64
+	Name string
+	Nick string `sql:"column:name"`
+}
+
+func TestSQLColumnNameErrors(t *testing.T) {
+	h := New(&TestDuplicateColumnStruct{}, Options{})
+	if !errors.Is(h.Err(), duplicateColumnError) {
+		t.Fatalf("want duplicate column error, got %v", h.Err())
+	}
+}
+
+type HTTPServer struct {
+	ID      int64
+	HostURL string
+}
+
+func TestSQLNamingQueries(t *testing.T) {
+	h := New(&HTTPServer{}, Options{Naming: PluralNaming{}, PrefixPrimaryKey: true})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	got := h.CreateTable()
+	want := `CREATE TABLE IF NOT EXISTS "http_servers" ("http_servers_id" SERIAL PRIMARY KEY,"host_url" VARCHAR(255) NOT NULL DEFAULT '');`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+}
+
+type TestLegacyStruct struct {
+	ID   int64
+	Name string
+}
+
+func (s TestLegacyStruct) TableName() string {
+	return "tbl_legacy"
+}
+
+func TestSQLTableNameQueries(t *testing.T) {
+	tests := []struct {
+		obj     interface{}
+		options Options
+		want    string
+	}{
+		{&TestStruct{}, Options{StructName: "Product"}, `DROP TABLE IF EXISTS "product";`},
+		{&TestStruct{}, Options{StructName: "Product", TableName: "items", TableNamePrefix: "shop_"}, `DROP TABLE IF EXISTS "shop_items";`},
+		{&TestLegacyStruct{}, Options{}, `DROP TABLE IF EXISTS "tbl_legacy";`},
+		{&TestLegacyStruct{}, Options{TableName: "legacy"}, `DROP TABLE IF EXISTS "legacy";`},
+	}
+	for _, test := range tests {
+		h := New(test.obj, test.options)
+		if h.Err() != nil {
+			t.Fatalf("unexpected error: %v", h.Err())
+		}
+		if got := h.DropTable(); got != test.want {
+			t.Fatalf("\nwant %v\ngot  %v", test.want, got)
+		}
+	}
+
+	h := New(&TestStruct{}, Options{StructName: "Product", PrefixPrimaryKey: true})
+	if got := h.DatabaseColumnToFieldName("product_id"); got != "ID" {
+		t.Fatalf("want ID, got %v", got)
+	}
+
+	h = New(&TestPrefix_Register{}, Options{TableNamePrefix: "app_", PrefixPrimaryKey: true})
+	want := `SELECT "test_prefix_id","name" FROM "app_test_prefix" WHERE "test_prefix_id" = $1;`
+	if h.SelectByID() != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, h.SelectByID())
+	}
+}
+
+type TestPrefix_Register struct {
+	ID   int64
+	Name string
+}
+
+func TestSQLSchemaQueries(t *testing.T) {
+	userBuilder := New(&TestFKUser{}, Options{TableNamePrefix: "app_", PrefixPrimaryKey: true})
+	h := New(&TestFKOrder{}, Options{Schema: "tenant1", References: map[string]*Builder{"User": userBuilder}})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	tests := [][2]string{
+		{h.CreateTable(), `CREATE TABLE IF NOT EXISTS "tenant1"."test_f_k_order" ("id" SERIAL PRIMARY KEY,` +
+			`"user_id" BIGINT NOT NULL REFERENCES "tenant1"."app_test_f_k_user"("test_f_k_user_id") ON DELETE CASCADE,` +
+			`"reviewer_id" BIGINT REFERENCES "tenant1"."TestFKUser"("id") ON DELETE SET NULL ON UPDATE CASCADE,` +
+			`"currency" VARCHAR(255) NOT NULL REFERENCES "tenant1"."currency");`},
+		{h.SelectByID(), `SELECT "id","user_id","reviewer_id","currency" FROM "tenant1"."test_f_k_order" WHERE "id" = $1;`},
+	}
+
+	h2 := h.WithSchema(`tenant"2`)
+	tests = append(tests, [][2]string{
+		{h2.DeleteByID(), `DELETE FROM "tenant""2"."test_f_k_order" WHERE "id" = $1;`},
+		{h2.Insert(), `INSERT INTO "tenant""2"."test_f_k_order"("user_id","reviewer_id","currency") VALUES ($1,$2,$3) RETURNING "id";`},
+		{h.DeleteByID(), `DELETE FROM "tenant1"."test_f_k_order" WHERE "id" = $1;`},
+		{New(&TestIndexStruct{}, Options{}).WithSchema("tenant1").DropIndexes()[0], `DROP INDEX IF EXISTS "tenant1"."test_index_struct_email_idx";`},
+		{New(&TestIndexStruct{}, Options{}).WithSchema("tenant1").CreateIndexes()[0], `CREATE INDEX IF NOT EXISTS "test_index_struct_email_idx" ON "tenant1"."test_index_struct" ("email");`},
+	}...)
+	for _, test := range tests {
+		if test[0] != test[1] {
+			t.Fatalf("\nwant %v\ngot  %v", test[1], test[0])
+		}
+	}
+}
+
+type TestOrderStatus string
+
+type TestEnumStruct struct {
+	ID       int64
+	Status   TestOrderStatus
+	Priority *string `sql:"enum:low|normal|high"`
+	Kind     string  `sql:"enum:a|b" sql_val:"b"`
+}
+
+func TestSQLEnumQueries(t *testing.T) {
+	RegisterEnum(TestOrderStatus(""), "new", "paid", "shipped")
+
+	h := New(&TestEnumStruct{}, Options{})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	tests := [][2]string{
+		{strings.Join(h.CreateTypes(), "\n"), `DO $$ BEGIN CREATE TYPE "test_order_status" AS ENUM ('new','paid','shipped'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;` + "\n" +
+			`DO $$ BEGIN CREATE TYPE "test_enum_struct_priority" AS ENUM ('low','normal','high'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;` + "\n" +
+			`DO $$ BEGIN CREATE TYPE "test_enum_struct_kind" AS ENUM ('a','b'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;`},
+		{h.CreateTable(), `CREATE TABLE IF NOT EXISTS "test_enum_struct" ("id" SERIAL PRIMARY KEY,"status" "test_order_status" NOT NULL DEFAULT 'new',"priority" "test_enum_struct_priority","kind" "test_enum_struct_kind" NOT NULL DEFAULT 'b');`},
+		{h.WithSchema("tenant1").CreateTable(), `CREATE TABLE IF NOT EXISTS "tenant1"."test_enum_struct" ("id" SERIAL PRIMARY KEY,"status" "tenant1"."test_order_status" NOT NULL DEFAULT 'new',"priority" "tenant1"."test_enum_struct_priority","kind" "tenant1"."test_enum_struct_kind" NOT NULL DEFAULT 'b');`},
+		{strings.Join(h.DropTypes(), "\n"), `DROP TYPE IF EXISTS "test_order_status";` + "\n" + `DROP TYPE IF EXISTS "test_enum_struct_priority";` + "\n" + `DROP TYPE IF EXISTS "test_enum_struct_kind";`},
+		{strings.Join(h.AlterTypes(map[string][]string{"test_order_status": {"new", "shipped"}, "test_enum_struct_priority": {"normal", "high"}}), "\n"),
+			`ALTER TYPE "test_order_status" ADD VALUE IF NOT EXISTS 'paid' AFTER 'new';` + "\n" + `ALTER TYPE "test_enum_struct_priority" ADD VALUE IF NOT EXISTS 'low' BEFORE 'normal';`},
+		{strings.Join(h.AlterTypes(map[string][]string{"test_enum_struct_priority": {"high"}}), "\n"),
+			`ALTER TYPE "test_enum_struct_priority" ADD VALUE IF NOT EXISTS 'low' BEFORE 'high';` + "\n" + `ALTER TYPE "test_enum_struct_priority" ADD VALUE IF NOT EXISTS 'normal' AFTER 'low';`},
+	}
+
+	got, err := h.Select(nil, 0, 0, &Filters{"Status": {Op: OpAny, Val: []TestOrderStatus{"new", "paid"}}, "Kind": {Op: OpLike, Val: "a%"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests = append(tests, [2]string{got, `SELECT "id","status","priority","kind" FROM "test_enum_struct" WHERE CAST("kind" AS TEXT) LIKE $1 AND "status" = ANY($2);`})
+
+	for _, test := range tests {
+		if test[0] != test[1] {
+			t.Fatalf("\nwant %v\ngot  %v", test[1], test[0])
+		}
+	}
+
+	_, err = h.Select(nil, 0, 0, &Filters{"Status": {Op: OpEqual, Val: TestOrderStatus("lost")}})
+	if !errors.Is(err, enumError) {
+		t.Fatalf("want enum error, got %v", err)
+	}
+}
+
+type TestQuotedEnumStruct struct {
+	ID                                   int64
+	CustomerAccountReferenceProgressStep string `sql:"enum:'in progress'|'won''t do'|done"`
+}
+
+func TestSQLQuotedEnumQueries(t *testing.T) {
+	h := New(&TestQuotedEnumStruct{}, Options{})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	got := h.CreateTypes()[0]
+	start := strings.Index(got, `CREATE TYPE "`) + len(`CREATE TYPE "`)
+	name := got[start : start+strings.Index(got[start:], `"`)]
+	if len(name) > 63 || !strings.HasPrefix(name, "test_quoted_enum_struct_customer_account_reference_") {
+		t.Fatalf("want enum type name truncated to 63 bytes, got %v", name)
+	}
+
+	tests := [][2]string{
+		{got, `DO $$ BEGIN CREATE TYPE "` + name + `" AS ENUM ('in progress','won''t do','done'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;`},
+		{h.CreateTable(), `CREATE TABLE IF NOT EXISTS "test_quoted_enum_struct" ("id" SERIAL PRIMARY KEY,"customer_account_reference_progress_step" "` + name + `" NOT NULL DEFAULT 'in progress');`},
+	}
+	for _, test := range tests {
+		if test[0] != test[1] {
+			t.Fatalf("\nwant %v\ngot  %v", test[1], test[0])
+		}
+	}
+}
+
+type TestInvalidEnumStruct struct {
+	ID   int64
+	Kind string `sql:"enum:a|b" sql_val:"c"`
+}
+
+func TestSQLEnumErrors(t *testing.T) {
+	h := New(&TestInvalidEnumStruct{}, Options{})
+	if !errors.Is(h.Err(), defaultValueError) {
+		t.Fatalf("want default value error, got %v", h.Err())
+	}
+}
+
+type TestGeneratedStruct struct {
+	ID         int64
+	Email      string
+	EmailLower string  `sql:"generated:lower(.Email) index"`
+	Price      float64 `sql:"type:NUMERIC(10,2)"`
+	Gross      float64 `sql:"generated:(.Price * 1.23) type:NUMERIC(10,2)"`
+}
+
+func TestSQLGeneratedQueries(t *testing.T) {
+	h := New(&TestGeneratedStruct{}, Options{})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	got, _ := h.Select(nil, 0, 0, &Filters{"EmailLower": {Op: OpEqual, Val: "a@b.c"}})
+	tests := [][2]string{
+		{h.CreateTable(), `CREATE TABLE IF NOT EXISTS "test_generated_struct" ("id" SERIAL PRIMARY KEY,"email" VARCHAR(255) NOT NULL DEFAULT '',"email_lower" VARCHAR(255) GENERATED ALWAYS AS (lower("email")) STORED,"price" NUMERIC(10,2) NOT NULL DEFAULT 0,"gross" NUMERIC(10,2) GENERATED ALWAYS AS (("price" * 1.23)) STORED);`},
+		{h.Insert(), `INSERT INTO "test_generated_struct"("email","price") VALUES ($1,$2) RETURNING "id";`},
+		{h.UpdateByID(), `UPDATE "test_generated_struct" SET "email"=$1,"price"=$2 WHERE "id" = $3;`},
+		{h.InsertOnConflictUpdate(), `INSERT INTO "test_generated_struct"("id","email","price") VALUES ($1,$2,$3) ON CONFLICT ("id") DO UPDATE SET "email"=$4,"price"=$5 RETURNING "id";`},
+		{h.SelectByID(), `SELECT "id","email","email_lower","price","gross" FROM "test_generated_struct" WHERE "id" = $1;`},
+		{got, `SELECT "id","email","email_lower","price","gross" FROM "test_generated_struct" WHERE "email_lower"=$1;`},
+		{strings.Join(h.InsertFields(), ","), "Email,Price"},
+	}
+	for _, test := range tests {
+		if test[0] != test[1] {
+			t.Fatalf("\nwant %v\ngot  %v", test[1], test[0])
+		}
+	}
+
+	_, err := h.Update(map[string]interface{}{"Gross": 1.0}, nil)
+	if !errors.Is(err, fieldNotWritableError) {
+		t.Fatalf("want field not writable error, got %v", err)
+	}
+}
+
+type TestCommentStruct struct {
+	ID    int64  `sql:"comment:Identifier"`
+	Email string `sql:"uniq comment:'User''s e-mail (lowercase)'"`
+	Path  string `sql:"comment:'C:\\temp'"`
+	Name  string
+}
+
+func (s *TestCommentStruct) TableComment() string {
+	return "Registered users"
+}
+
+func TestSQLCommentQueries(t *testing.T) {
+	h := New(&TestCommentStruct{}, Options{Schema: "app"})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	got := strings.Join(h.CommentStatements(), "\n")
+	want := `COMMENT ON TABLE "app"."test_comment_struct" IS 'Registered users';` + "\n" +
+		`COMMENT ON COLUMN "app"."test_comment_struct"."id" IS 'Identifier';` + "\n" +
+		`COMMENT ON COLUMN "app"."test_comment_struct"."email" IS 'User''s e-mail (lowercase)';` + "\n" +
+		`COMMENT ON COLUMN "app"."test_comment_struct"."path" IS E'C:\\temp';`
+	if got != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, got)
+	}
+}
+
+type TestAudit struct {
+	CreatedAt  int64
+	CreatedBy  int64
+	ModifiedAt int64
+	ModifiedBy int64
+}
+
+type TestAddress struct {
+	City    string `sql:"index"`
+	Country string `sql:"type:CHAR(2)"`
+}
+
+type TestEmbeddedStruct struct {
+	ID int64
+	TestAudit
+	Name     string
+	Address  TestAddress `sql:"prefix:address_"`
+	Shipping TestAddress
+}
+
+func TestSQLEmbeddedQueries(t *testing.T) {
+	h := New(&TestEmbeddedStruct{}, Options{})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	if !h.HasModificationFields() {
+		t.Fatalf("want modification fields from embedded struct")
+	}
+
+	got, _ := h.Select([]string{"Address.City", "asc"}, 0, 0, &Filters{
+		"Address.City": {Op: OpEqual, Val: "Oslo"},
+		"CreatedBy":    {Op: OpEqual, Val: 1},
+		Raw:            {Op: OpAND, Val: []interface{}{".Address.Country = ? OR .Shipping.city = ?", "NO", "Oslo"}},
+	})
+	tests := [][2]string{
+		{h.CreateTable(), `CREATE TABLE IF NOT EXISTS "test_embedded_struct" ("id" SERIAL PRIMARY KEY,"created_at" BIGINT NOT NULL DEFAULT 0,"created_by" BIGINT NOT NULL DEFAULT 0,"modified_at" BIGINT NOT NULL DEFAULT 0,"modified_by" BIGINT NOT NULL DEFAULT 0,"name" VARCHAR(255) NOT NULL DEFAULT '',"address_city" VARCHAR(255) NOT NULL DEFAULT '',"address_country" CHAR(2) NOT NULL DEFAULT '',"shipping" JSONB NOT NULL DEFAULT '{}');`},
+		{got, `SELECT "id","created_at","created_by","modified_at","modified_by","name","address_city","address_country","shipping" FROM "test_embedded_struct" WHERE ("address_city"=$1 AND "created_by"=$2) AND ("address_country" = $3 OR "shipping"->>'city' = $4) ORDER BY "address_city" ASC;`},
+		{strings.Join(h.CreateIndexes(), ""), `CREATE INDEX IF NOT EXISTS "test_embedded_struct_address_city_idx" ON "test_embedded_struct" ("address_city");`},
+		{h.DatabaseColumnToFieldName("address_country"), "Address.Country"},
+	}
+	for _, test := range tests {
+		if test[0] != test[1] {
+			t.Fatalf("\nwant %v\ngot  %v", test[1], test[0])
+		}
+	}
+}
+
+type TestWriteStruct struct {
+	ID        int64
+	Name      string
+	CreatedBy int64  `sql:"insertonly"`
+	Hits      int64  `sql:"readonly"`
+	Cache     string `sql:"-"`
+}
+
+func TestSQLWriteTagQueries(t *testing.T) {
+	h := New(&TestWriteStruct{}, Options{})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	got, _ := h.InsertOnConflict("ID", ConflictAction{Op: ConflictDoUpdate})
+	tests := [][2]string{
+		{h.CreateTable(), `CREATE TABLE IF NOT EXISTS "test_write_struct" ("id" SERIAL PRIMARY KEY,"name" VARCHAR(255) NOT NULL DEFAULT '',"created_by" BIGINT NOT NULL DEFAULT 0,"hits" BIGINT NOT NULL DEFAULT 0);`},
+		{h.Insert(), `INSERT INTO "test_write_struct"("name","created_by") VALUES ($1,$2) RETURNING "id";`},
+		{h.UpdateByID(), `UPDATE "test_write_struct" SET "name"=$1 WHERE "id" = $2;`},
+		{h.InsertOnConflictUpdate(), `INSERT INTO "test_write_struct"("id","name","created_by") VALUES ($1,$2,$3) ON CONFLICT ("id") DO UPDATE SET "name"=$4 RETURNING "id";`},
+		{got, `INSERT INTO "test_write_struct"("name","created_by") VALUES ($1,$2) ON CONFLICT ("id") DO UPDATE SET "name"=EXCLUDED."name" RETURNING "id";`},
+		{h.SelectByID(), `SELECT "id","name","created_by","hits" FROM "test_write_struct" WHERE "id" = $1;`},
+	}
+	for _, test := range tests {
+		if test[0] != test[1] {
+			t.Fatalf("\nwant %v\ngot  %v", test[1], test[0])
+		}
+	}
+
+	for _, fieldName := range []string{"CreatedBy", "Hits"} {
+		_, err := h.Update(map[string]interface{}{fieldName: 1}, nil)
+		if !errors.Is(err, fieldNotWritableError) {
+			t.Fatalf("want field not writable error for %s, got %v", fieldName, err)
+		}
+	}
+
+	_, err := h.Update(map[string]interface{}{"Cache": ""}, nil)
+	if !errors.Is(err, fieldNameNotFoundError) {
+		t.Fatalf("want field name not found error, got %v", err)
+	}
+}
+
+type TestStrictStruct struct {
+	ID       int64
+	Name     string `sql:"uniqe"`
+	Price    int64  `sql:"type:VARCHAR(10)"`
+	Callback func()
+	Tags     string `sql:"jsonb"`
+}
+
+type TestManyErrorsStruct struct {
+	ID     int64
+	Name   string `sql:"column:"`
+	Parent *int64 `sql:"pk"`
+	Kind   int64  `sql:"enum:a|b"`
+}
+
+type TestSingleNaturalKeyStruct struct {
+	Name string `sql:"pk"`
+}
+
+type TestNoPrimaryKeyStruct struct {
+	Name string
+	Age  int
+}
+
+func TestSQLStrictErrors(t *testing.T) {
+	h := New(&TestStrictStruct{}, Options{})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+	if len(h.Warnings()) != 4 {
+		t.Fatalf("want 4 warnings, got %v", h.Warnings())
+	}
+
+	h = New(&TestStrictStruct{}, Options{Strict: true})
+	for _, err := range []error{unknownTagOptionError, invalidTagValueError, unsupportedFieldError} {
+		if !errors.Is(h.Err(), err) {
+			t.Fatalf("want %v, got %v", err, h.Err())
+		}
+	}
+
+	var builderErr *BuilderError
+	if !errors.As(h.Err(), &builderErr) || builderErr.Field != "Callback" || builderErr.Tag != "sql" {
+		t.Fatalf("want error of Callback field, got %v", builderErr)
+	}
+
+	h = New(&TestManyErrorsStruct{}, Options{})
+	for _, err := range []error{columnNameError, primaryKeyError, enumError} {
+		if !errors.Is(h.Err(), err) {
+			t.Fatalf("want %v, got %v", err, h.Err())
+		}
+	}
+
+	h = New(&TestSingleNaturalKeyStruct{}, Options{})
+	if h.Err() != nil || len(h.Warnings()) != 1 || !errors.Is(h.Warnings()[0], noUpdateColumnsError) {
+		t.Fatalf("want no columns to update warning, got %v %v", h.Err(), h.Warnings())
+	}
+	tests := [][2]string{
+		{h.Insert(), `INSERT INTO "test_single_natural_key_struct"("name") VALUES ($1) RETURNING "name";`},
+		{h.InsertOnConflictUpdate(), `INSERT INTO "test_single_natural_key_struct"("name") VALUES ($1) ON CONFLICT ("name") DO NOTHING RETURNING "name";`},
+		{h.UpdateByID(), ""},
+		{h.UpdateByPK(), ""},
+	}
+	for _, test := range tests {
+		if test[0] != test[1] {
+			t.Fatalf("\nwant %v\ngot  %v", test[1], test[0])
+		}
+	}
+
+	h = New(&TestSingleNaturalKeyStruct{}, Options{Strict: true})
+	if !errors.Is(h.Err(), noUpdateColumnsError) {
+		t.Fatalf("want no columns to update error, got %v", h.Err())
+	}
+
+	h = New(&TestNoPrimaryKeyStruct{}, Options{})
+	if h.Err() != nil || len(h.Warnings()) != 1 || !errors.Is(h.Warnings()[0], noPrimaryKeyError) {
+		t.Fatalf("want primary key not found warning, got %v %v", h.Err(), h.Warnings())
+	}
+
+	for _, query := range []string{h.SelectByID(), h.UpdateByID(), h.DeleteByID(), h.InsertOnConflictUpdate(), h.SelectByPK(),
+		h.UpdateByPK(), h.DeleteByPK(), h.RestoreByID(), h.HardDeleteByID()} {
+		if query != "" {
+			t.Fatalf("want no query without a primary key, got %v", query)
+		}
+	}
+
+	h = New(&TestNoPrimaryKeyStruct{}, Options{Strict: true})
+	if !errors.Is(h.Err(), noPrimaryKeyError) {
+		t.Fatalf("want primary key not found error, got %v", h.Err())
+	}
+}
+
+type TestTimestampStruct struct {
+	ID         int64
+	Name       string
+	CreatedAt  time.Time
+	CreatedBy  int64
+	ModifiedAt int64
+	ModifiedBy int64
+}
+
+type TestInvalidTimestampStruct struct {
+	ID        int64 `sql:"pk:uuid"`
+	CreatedAt int64
+}
+
+func TestSQLAutoTimestampErrors(t *testing.T) {
+	h := New(&TestInvalidTimestampStruct{}, Options{AutoTimestamps: true})
+	if !errors.Is(h.Err(), primaryKeyError) {
+		t.Fatalf("want invalid primary key error, got %v", h.Err())
+	}
+}
+
+func TestSQLAutoTimestampQueries(t *testing.T) {
+	h := New(&TestTimestampStruct{}, Options{AutoTimestamps: true})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	if !h.HasModificationFields() {
+		t.Fatalf("want modification fields with time.Time CreatedAt")
+	}
+
+	epoch := "CAST(EXTRACT(EPOCH FROM now()) AS BIGINT)"
+	got, _ := h.Update(map[string]interface{}{"Name": "x", "ModifiedBy": 2}, &Filters{"ID": {Op: OpEqual, Val: 1}})
+	conflict, _ := h.InsertOnConflict("ID", ConflictAction{Op: ConflictDoUpdate})
+	tests := [][2]string{
+		{h.Insert(), `INSERT INTO "test_timestamp_struct"("name","created_by","modified_by","created_at","modified_at") VALUES ($1,$2,$3,now(),` + epoch + `) RETURNING "id";`},
+		{h.UpdateByID(), `UPDATE "test_timestamp_struct" SET "name"=$1,"modified_by"=$2,"modified_at"=` + epoch + ` WHERE "id" = $3;`},
+		{h.InsertOnConflictUpdate(), `INSERT INTO "test_timestamp_struct"("id","name","created_by","modified_by","created_at","modified_at") VALUES ($1,$2,$3,$4,now(),` + epoch + `) ON CONFLICT ("id") DO UPDATE SET "name"=$5,"modified_by"=$6,"modified_at"=` + epoch + ` RETURNING "id";`},
+		{conflict, `INSERT INTO "test_timestamp_struct"("name","created_by","modified_by","created_at","modified_at") VALUES ($1,$2,$3,now(),` + epoch + `) ON CONFLICT ("id") DO UPDATE SET "name"=EXCLUDED."name","modified_by"=EXCLUDED."modified_by","modified_at"=EXCLUDED."modified_at" RETURNING "id";`},
+		{got, `UPDATE "test_timestamp_struct" SET "modified_by"=$1,"name"=$2,"modified_at"=` + epoch + ` WHERE "id"=$3;`},
+		{strings.Join(h.InsertFields(), ","), "Name,CreatedBy,ModifiedBy"},
+	}
+	for _, test := range tests {
+		if test[0] != test[1] {
+			t.Fatalf("\nwant %v\ngot  %v", test[1], test[0])
+		}
+	}
+
+	for _, fieldName := range []string{"CreatedAt", "CreatedBy", "ModifiedAt"} {
+		_, err := h.Update(map[string]interface{}{fieldName: 1}, nil)
+		if !errors.Is(err, fieldNotWritableError) {
+			t.Fatalf("want field not writable error for %s, got %v", fieldName, err)
+		}
+	}
+
+	h = New(&TestTimestampStruct{}, Options{})
+	want := `UPDATE "test_timestamp_struct" SET "name"=$1,"created_at"=$2,"created_by"=$3,"modified_at"=$4,"modified_by"=$5 WHERE "id" = $6;`
+	if h.UpdateByID() != want {
+		t.Fatalf("\nwant %v\ngot  %v", want, h.UpdateByID())
+	}
+}
+
+type TestSoftDeleteStruct struct {
+	ID        int64
+	Name      string
+	DeletedAt *time.Time
+}
+
+type TestTaggedSoftDeleteStruct struct {
+	ID         int64
+	Name       string
+	ModifiedAt int64
+	Removed    int64 `sql:"deleted"`
+}
+
+type TestInvalidSoftDeleteStruct struct {
+	ID      int64
+	Removed time.Time `sql:"deleted"`
+}
+
+func TestSQLSoftDeleteQueries(t *testing.T) {
+	h := New(&TestSoftDeleteStruct{}, Options{SoftDelete: true})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	filters := &Filters{"Name": {Op: OpEqual, Val: "x"}}
+	selected, _ := h.Select(nil, 0, 0, filters)
+	counted, _ := h.SelectCount(nil)
+	deleted, _ := h.Delete(filters)
+	deletedID, _ := h.DeleteReturningID(filters)
+	restored, _ := h.Restore(filters)
+	hardDeleted, _ := h.HardDelete(filters)
+	withDeleted, _ := h.WithDeleted().Select(nil, 0, 0, filters)
+	onlyDeleted, _ := h.OnlyDeleted().SelectCount(filters)
+	tests := [][2]string{
+		{h.Insert(), `INSERT INTO "test_soft_delete_struct"("name") VALUES ($1) RETURNING "id";`},
+		{h.UpdateByID(), `UPDATE "test_soft_delete_struct" SET "name"=$1 WHERE "id" = $2;`},
+		{h.SelectByID(), `SELECT "id","name","deleted_at" FROM "test_soft_delete_struct" WHERE ("id" = $1) AND "deleted_at" IS NULL;`},
+		{h.DeleteByID(), `UPDATE "test_soft_delete_struct" SET "deleted_at"=now() WHERE ("id" = $1) AND "deleted_at" IS NULL;`},
+		{h.RestoreByID(), `UPDATE "test_soft_delete_struct" SET "deleted_at"=NULL WHERE ("id" = $1) AND "deleted_at" IS NOT NULL;`},
+		{h.HardDeleteByID(), `DELETE FROM "test_soft_delete_struct" WHERE "id" = $1;`},
+		{selected, `SELECT "id","name","deleted_at" FROM "test_soft_delete_struct" WHERE ("name"=$1) AND "deleted_at" IS NULL;`},
+		{counted, `SELECT COUNT(*) AS cnt FROM "test_soft_delete_struct" WHERE "deleted_at" IS NULL;`},
+		{deleted, `UPDATE "test_soft_delete_struct" SET "deleted_at"=now() WHERE ("name"=$1) AND "deleted_at" IS NULL;`},
+		{deletedID, `UPDATE "test_soft_delete_struct" SET "deleted_at"=now() WHERE ("name"=$1) AND "deleted_at" IS NULL RETURNING "id";`},
+		{restored, `UPDATE "test_soft_delete_struct" SET "deleted_at"=NULL WHERE ("name"=$1) AND "deleted_at" IS NOT NULL;`},
+		{hardDeleted, `DELETE FROM "test_soft_delete_struct" WHERE "name"=$1;`},
+		{withDeleted, `SELECT "id","name","deleted_at" FROM "test_soft_delete_struct" WHERE "name"=$1;`},
+		{h.WithDeleted().SelectByID(), `SELECT "id","name","deleted_at" FROM "test_soft_delete_struct" WHERE "id" = $1;`},
+		{h.OnlyDeleted().HardDeleteByID(), `DELETE FROM "test_soft_delete_struct" WHERE "id" = $1;`},
+		{onlyDeleted, `SELECT COUNT(*) AS cnt FROM "test_soft_delete_struct" WHERE ("name"=$1) AND "deleted_at" IS NOT NULL;`},
+	}
+	for _, test := range tests {
+		if test[0] != test[1] {
+			t.Fatalf("\nwant %v\ngot  %v", test[1], test[0])
+		}
+	}
+
+	_, err := h.Update(map[string]interface{}{"DeletedAt": nil}, nil)
+	if !errors.Is(err, fieldNotWritableError) {
+		t.Fatalf("want field not writable error, got %v", err)
+	}
+
+	h = New(&TestTaggedSoftDeleteStruct{}, Options{AutoTimestamps: true})
+	epoch := "CAST(EXTRACT(EPOCH FROM now()) AS BIGINT)"
+	tests = [][2]string{
+		{h.DeleteByID(), `UPDATE "test_tagged_soft_delete_struct" SET "removed"=` + epoch + `,"modified_at"=` + epoch + ` WHERE ("id" = $1) AND "removed"=0;`},
+		{h.RestoreByID(), `UPDATE "test_tagged_soft_delete_struct" SET "removed"=0,"modified_at"=` + epoch + ` WHERE ("id" = $1) AND "removed"<>0;`},
+	}
+	for _, test := range tests {
+		if test[0] != test[1] {
+			t.Fatalf("\nwant %v\ngot  %v", test[1], test[0])
+		}
+	}
+
+	h = New(&TestSoftDeleteStruct{}, Options{})
+	deleted, _ = h.Delete(filters)
+	tests = [][2]string{
+		{h.DeleteByID(), `DELETE FROM "test_soft_delete_struct" WHERE "id" = $1;`},
+		{deleted, `DELETE FROM "test_soft_delete_struct" WHERE "name"=$1;`},
+	}
+	for _, test := range tests {
+		if test[0] != test[1] {
+			t.Fatalf("\nwant %v\ngot  %v", test[1], test[0])
+		}
+	}
+
+	_, err = h.Restore(filters)
+	if !errors.Is(err, noSoftDeleteError) {
+		t.Fatalf("want soft delete field not found error, got %v", err)
+	}
+}
+
+type TestInvalidKeySoftDeleteStruct struct {
+	ID        int64 `sql:"pk:uuid"`
+	DeletedAt *time.Time
+}
+
+func TestSQLSoftDeleteErrors(t *testing.T) {
+	h := New(&TestInvalidSoftDeleteStruct{}, Options{})
+	if !errors.Is(h.Err(), softDeleteError) {
+		t.Fatalf("want invalid soft delete field error, got %v", h.Err())
+	}
+
+	h = New(&TestInvalidKeySoftDeleteStruct{}, Options{SoftDelete: true})
+	if !errors.Is(h.Err(), primaryKeyError) {
+		t.Fatalf("want invalid primary key error, got %v", h.Err())
+	}
+}
+
+type TestVersionStruct struct {
+	ID         int64
+	Name       string
+	ModifiedAt time.Time
+	Version    int64 `sql:"version"`
+}
+
+type TestStringVersionStruct struct {
+	ID      int64
+	Version string `sql:"version"`
+}
+
+type TestPointerVersionStruct struct {
+	ID      int64
+	Version *int64 `sql:"version"`
+}
+
+type TestDoubleVersionStruct struct {
+	ID       int64
+	Version  int64 `sql:"version"`
+	Revision int64 `sql:"version"`
+}
+
+func TestSQLVersionQueries(t *testing.T) {
+	h := New(&TestVersionStruct{}, Options{AutoTimestamps: true})
+	if h.Err() != nil {
+		t.Fatalf("unexpected error: %v", h.Err())
+	}
+
+	updated, _ := h.Update(map[string]interface{}{"Name": "x", "Version": 3}, &Filters{"ID": {Op: OpEqual, Val: 1}})
+	updatedAll, _ := h.Update(map[string]interface{}{"Name": "x"}, nil)
+	conflict, _ := h.InsertOnConflict("ID", ConflictAction{Op: ConflictDoUpdate})
+	tests := [][2]string{
+		{h.Insert(), `INSERT INTO "test_version_struct"("name","modified_at") VALUES ($1,now()) RETURNING "id";`},
+		{h.UpdateByID(), `UPDATE "test_version_struct" SET "name"=$1,"modified_at"=now(),"version"="version"+1 WHERE "id" = $2 AND "version" = $3 RETURNING "version";`},
+		{h.InsertOnConflictUpdate(), `INSERT INTO "test_version_struct"("id","name","modified_at") VALUES ($1,$2,now()) ON CONFLICT ("id") DO UPDATE SET "name"=$3,"modified_at"=now(),"version"="test_version_struct"."version"+1 WHERE "test_version_struct"."version" = $4 RETURNING "id","version";`},
+		{conflict, `INSERT INTO "test_version_struct"("name","modified_at") VALUES ($1,now()) ON CONFLICT ("id") DO UPDATE SET "name"=EXCLUDED."name","modified_at"=EXCLUDED."modified_at","version"="test_version_struct"."version"+1 RETURNING "id","version";`},
+		{updated, `UPDATE "test_version_struct" SET "name"=$1,"modified_at"=now(),"version"="version"+1 WHERE ("id"=$3) AND "version"=$2 RETURNING "version";`},
+		{updatedAll, `UPDATE "test_version_struct" SET "name"=$1,"modified_at"=now(),"version"="version"+1;`},
+		{strings.Join(h.UpdateFields(), ","), "Name"},
+		{h.VersionField(), "Version"},
+	}
+	for _, test := range tests {
+		if test[0] != test[1] {
+			t.Fatalf("\nwant %v\ngot  %v", test[1], test[0])
+		}
+	}
+
+	var conflictErr *VersionConflictError
+	if !errors.As(h.CheckVersion(0, 3), &conflictErr) || conflictErr.Expected != 3 || conflictErr.Table != "test_version_struct" {
+		t.Fatalf("want version conflict error, got %v", h.CheckVersion(0, 3))
+	}
+
+	err := h.WithSchema("tenant").CheckVersion(0, 3)
+	if !errors.As(err, &conflictErr) || conflictErr.Schema != "tenant" || conflictErr.Table != "test_version_struct" ||
+		err.Error() != "version conflict: row in tenant.test_version_struct table is not at version 3" {
+		t.Fatalf("want version conflict error in tenant schema, got %v", err)
+	}
+	if h.CheckVersion(1, 3) != nil {
+		t.Fatalf("unexpected error: %v", h.CheckVersion(1, 3))
+	}
+}
+
+func TestSQLVersionErrors(t *testing.T) {
+	for _, obj := range []interface{}{&TestStringVersionStruct{}, &TestPointerVersionStruct{}, &TestDoubleVersionStruct{}} {
+		h := New(obj, Options{})
+		if !errors.Is(h.Err(), versionError) {
+			t.Fatalf("want invalid version field error for %T, got %v", obj, h.Err())
+		}
+	}
+}
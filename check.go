@@ -0,0 +1,97 @@
+package pgsqlbuilder
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// check is a CHECK constraint declared with check tag, or added to unsigned integer fields.
+type check struct {
+	name       string
+	expression string
+}
+
+// reflectChecks resolves expressions of check constraints once all the columns are known.
+// Constraints are named the way PostgreSQL names column constraints, eg. product_price_check, product_price_check1.
+func (b *Builder) reflectChecks() {
+	for _, fieldName := range b.fieldNames {
+		expressions := make([]string, 0, 2)
+
+		// Unsigned integer values cannot be negative
+		fieldType, ok := b.fieldTypes[fieldName]
+		if ok && isUnsignedKind(fieldType.Kind()) && !isGeneratedPrimaryKey(b.fieldPrimaryKeyType[fieldName]) {
+			expression := fmt.Sprintf(`"%s" >= 0`, b.fieldColumnName[fieldName])
+
+			// NUMERIC(20,0) column of uint64 and uint fields can hold larger values than they can
+			if fieldType.Kind() == reflect.Uint64 || fieldType.Kind() == reflect.Uint {
+				expression += fmt.Sprintf(` AND "%s" <= %d`, b.fieldColumnName[fieldName], uint64(math.MaxUint64))
+			}
+			expressions = append(expressions, expression)
+		}
+
+		tagExpression, ok := b.fieldChecks[fieldName]
+		if ok {
+			expression, err := b.checkExpression(fieldName, tagExpression)
+			if err != nil {
+				b.addError(getTagBuilderError(fieldName, b.tagName, err))
+				continue
+			}
+			expressions = append(expressions, expression)
+		}
+
+		for i, expression := range expressions {
+			name := b.tableBaseName + "_" + b.fieldColumnName[fieldName] + "_check"
+			if i > 0 {
+				name += fmt.Sprintf("%d", i)
+			}
+			b.checks = append(b.checks, &check{name: shortIdentifier(name), expression: expression})
+		}
+	}
+}
+
+// checkExpression returns a condition from check tag.  A value starting with an operator, eg. >=0, is compared with
+// the column of the field, and any other value is an expression where fields are replaced with columns, eg. .Price > .Cost
+func (b *Builder) checkExpression(fieldName string, tagExpression string) (string, error) {
+	expression := strings.TrimSpace(tagExpression)
+	if regexpCheckOperator.MatchString(expression) {
+		if strings.HasPrefix(expression, "(") && strings.HasSuffix(expression, ")") {
+			expression = strings.TrimSpace(expression[1 : len(expression)-1])
+		}
+
+		operator := regexpCheckOperator.FindStringSubmatch(expression)[1]
+		value := strings.TrimSpace(strings.TrimPrefix(expression, operator))
+		if value == "" {
+			return "", fmt.Errorf("%w: missing value in %s", checkError, tagExpression)
+		}
+
+		return fmt.Sprintf(`"%s" %s %s`, b.fieldColumnName[fieldName], operator, value), nil
+	}
+
+	if expression == "" {
+		return "", fmt.Errorf("%w: empty expression", checkError)
+	}
+
+	return b.resolveFields(expression, "check constraint")
+}
+
+// tableChecks returns CHECK constraints for the table definition.
+func (b *Builder) tableChecks() []string {
+	checks := make([]string, 0, len(b.checks))
+	for _, c := range b.checks {
+		checks = append(checks, fmt.Sprintf("CONSTRAINT %s CHECK (%s)", quoteIdentifier(c.name), c.expression))
+	}
+
+	return checks
+}
+
+// isUnsignedKind checks if a specific reflect kind is an unsigned integer.
+func isUnsignedKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,41 @@
+package pgsqlbuilder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tableCommenter is implemented by structs that describe their table with TableComment method.
+type tableCommenter interface {
+	TableComment() string
+}
+
+// commentFromTag returns a comment from comment tag, which can be wrapped in single quotes to contain spaces,
+// eg. comment:'Email of the user'.
+func commentFromTag(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+
+	return value
+}
+
+// CommentStatements returns SQL queries for setting comments of the table and its columns, which are run after CreateTable.
+func (b *Builder) CommentStatements() []string {
+	queries := make([]string, 0, len(b.fieldComment)+1)
+	if b.tableComment != "" {
+		queries = append(queries, fmt.Sprintf("COMMENT ON TABLE %s IS %s;", b.tableName, QuoteLiteral(b.tableComment)))
+	}
+
+	for _, fieldName := range b.fieldNames {
+		comment, ok := b.fieldComment[fieldName]
+		if !ok {
+			continue
+		}
+
+		queries = append(queries, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", b.tableName,
+			quoteIdentifier(b.fieldColumnName[fieldName]), QuoteLiteral(comment)))
+	}
+
+	return queries
+}
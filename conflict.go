@@ -0,0 +1,72 @@
+package pgsqlbuilder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConflictAction is an action taken by InsertOnConflict when the inserted row conflicts with an existing one.
+// With ConflictDoUpdate, Fields are updated with the inserted values.  When Fields are empty, all the fields from UpdateFields()
+// except the conflict target are updated.
+type ConflictAction struct {
+	Op     int
+	Fields []string
+}
+
+const (
+	ConflictDoNothing = iota * 1
+	ConflictDoUpdate
+)
+
+// conflictTargetFields returns fields of a unique group, a unique field or a primary key field.
+func (b *Builder) conflictTargetFields(target string) ([]string, error) {
+	groupFields, ok := b.uniqueGroups[target]
+	if ok {
+		return groupFields, nil
+	}
+
+	if b.fieldFlags[target]&FieldFlagUnique > 0 {
+		return []string{target}, nil
+	}
+
+	// Field of a composite primary key is not unique on its own
+	if b.fieldFlags[target]&FieldFlagPrimaryKey > 0 && len(b.primaryKeyFields) == 1 {
+		return []string{target}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s is not unique", conflictTargetError, target)
+}
+
+// queryConflictAction returns DO NOTHING or DO UPDATE SET with columns set to EXCLUDED values.  Version is incremented
+// without checking it.
+func (b *Builder) queryConflictAction(targetFields []string, action ConflictAction) (string, error) {
+	if action.Op != ConflictDoUpdate {
+		return "DO NOTHING", nil
+	}
+
+	updateFields := action.Fields
+	if len(updateFields) == 0 {
+		for _, fieldName := range b.updateFields {
+			if !containsString(targetFields, fieldName) {
+				updateFields = append(updateFields, fieldName)
+			}
+		}
+	}
+
+	autoSet := joinNotEmpty(",", b.autoValueSet(true), b.versionSet(true))
+	if len(updateFields) == 0 && autoSet == "" {
+		return "DO NOTHING", nil
+	}
+
+	set := make([]string, 0, len(updateFields)+1)
+	for _, fieldName := range updateFields {
+		if !containsString(b.updateFields, fieldName) {
+			return "", fmt.Errorf("%w: %s cannot be updated", conflictActionError, fieldName)
+		}
+
+		column := b.fieldColumnName[fieldName]
+		set = append(set, fmt.Sprintf(`"%s"=EXCLUDED."%s"`, column, column))
+	}
+
+	return "DO UPDATE SET " + joinNotEmpty(",", strings.Join(set, ","), autoSet), nil
+}
@@ -10,8 +10,25 @@ const (
 	FieldFlagUnique
 	FieldFlagNotString
 	FieldFlagPassword
+	FieldFlagNullable
+	FieldFlagArray
+	FieldFlagJSON
+	FieldFlagPrimaryKey
+	FieldFlagGenerated
+	FieldFlagReadOnly
+	FieldFlagInsertOnly
+	FieldFlagSoftDelete
+	FieldFlagVersion
 )
 
 const (
 	DefaultTagName = "sql"
 )
+
+const (
+	primaryKeySerial    = "serial"
+	primaryKeyBigSerial = "bigserial"
+	primaryKeyIdentity  = "identity"
+	primaryKeyUUID      = "uuid"
+	primaryKeyNatural   = "natural"
+)
@@ -0,0 +1,189 @@
+package pgsqlbuilder
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// enum is a PostgreSQL ENUM type declared with enum tag, or registered with RegisterEnum.
+type enum struct {
+	name   string
+	values []string
+}
+
+var (
+	registeredEnums   = map[reflect.Type][]string{}
+	registeredEnumsMu sync.RWMutex
+)
+
+// RegisterEnum registers allowed values of a string type, eg. RegisterEnum(Status(""), "draft", "published").
+// Fields of that type become columns of ENUM type, named after the type.  It must be called before New.
+func RegisterEnum(value interface{}, values ...string) {
+	registeredEnumsMu.Lock()
+	defer registeredEnumsMu.Unlock()
+
+	registeredEnums[reflect.TypeOf(value)] = append([]string{}, values...)
+}
+
+// registeredEnumValues returns values registered for a type.
+func registeredEnumValues(t reflect.Type) ([]string, bool) {
+	registeredEnumsMu.RLock()
+	defer registeredEnumsMu.RUnlock()
+
+	values, ok := registeredEnums[t]
+	return values, ok
+}
+
+// setFieldEnum makes the field an ENUM column when its values are set in the tag or registered for its type.
+// Named string type gives the name to the enum, and otherwise the name is made of table and column names.
+func (b *Builder) setFieldEnum(fieldName string, fieldType reflect.Type, columnName string) {
+	values, ok := b.fieldEnumValues[fieldName]
+	if !ok {
+		values, ok = registeredEnumValues(fieldType)
+	}
+	if !ok {
+		return
+	}
+
+	if fieldType.Kind() != reflect.String || len(values) == 0 {
+		b.addError(getTagBuilderError(fieldName, b.tagName, fmt.Errorf("%w: enum requires a string field and values", enumError)))
+		return
+	}
+
+	name := b.tableBaseName + "_" + columnName
+	if fieldType.Name() != "" && fieldType.Name() != "string" {
+		name = b.naming.ColumnName(fieldType.Name())
+	}
+	name = shortIdentifier(name)
+
+	for _, e := range b.enums {
+		if e.name != name {
+			continue
+		}
+
+		if strings.Join(e.values, "|") != strings.Join(values, "|") {
+			b.addError(getTagBuilderError(fieldName, b.tagName, fmt.Errorf("%w: conflicting values of %s", enumError, name)))
+			return
+		}
+		b.fieldEnum[fieldName] = e
+		return
+	}
+
+	e := &enum{name: name, values: values}
+	b.enums = append(b.enums, e)
+	b.fieldEnum[fieldName] = e
+}
+
+// hasValue checks if a value is allowed by the enum.
+func (e *enum) hasValue(value string) bool {
+	return containsString(e.values, value)
+}
+
+// validateEnumValue checks if a filter value, or every item of a slice value, is allowed by the enum of the field.
+func (b *Builder) validateEnumValue(fieldName string, value interface{}) error {
+	e, ok := b.fieldEnum[fieldName]
+	if !ok {
+		return nil
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(value))
+	values := []reflect.Value{v}
+	if v.Kind() == reflect.Slice {
+		values = make([]reflect.Value, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, reflect.Indirect(v.Index(i)))
+		}
+	}
+
+	for _, item := range values {
+		if item.Kind() != reflect.String || !e.hasValue(item.String()) {
+			return fmt.Errorf("%w: %v is not a value of %s", enumError, item, e.name)
+		}
+	}
+
+	return nil
+}
+
+// enumColumnDefinition qualifies ENUM type of the column with the schema, which can change with WithSchema.
+func (b *Builder) enumColumnDefinition(fieldName string, definition string) string {
+	e, ok := b.fieldEnum[fieldName]
+	if !ok || b.schema == "" {
+		return definition
+	}
+
+	return strings.Replace(definition, " "+quoteIdentifier(e.name), " "+qualifiedName(b.schema, e.name), 1)
+}
+
+// Enums returns a map with names of ENUM types and their values.
+func (b *Builder) Enums() map[string][]string {
+	enums := make(map[string][]string, len(b.enums))
+	for _, e := range b.enums {
+		enums[e.name] = e.values
+	}
+
+	return enums
+}
+
+// CreateTypes returns SQL queries for creating ENUM types, which must be run before CreateTable.
+// PostgreSQL does not support IF NOT EXISTS for types, so an existing type is ignored in a DO block.
+func (b *Builder) CreateTypes() []string {
+	queries := make([]string, 0, len(b.enums))
+	for _, e := range b.enums {
+		values := make([]string, 0, len(e.values))
+		for _, value := range e.values {
+			values = append(values, QuoteLiteral(value))
+		}
+
+		queries = append(queries, fmt.Sprintf("DO $$ BEGIN CREATE TYPE %s AS ENUM (%s); EXCEPTION WHEN duplicate_object THEN NULL; END $$;",
+			qualifiedName(b.schema, e.name), strings.Join(values, ",")))
+	}
+
+	return queries
+}
+
+// DropTypes returns SQL queries for dropping ENUM types, which must be run after DropTable.
+func (b *Builder) DropTypes() []string {
+	queries := make([]string, 0, len(b.enums))
+	for _, e := range b.enums {
+		queries = append(queries, fmt.Sprintf("DROP TYPE IF EXISTS %s;", qualifiedName(b.schema, e.name)))
+	}
+
+	return queries
+}
+
+// AlterTypes returns SQL queries for adding values to ENUM types, which were not in the previous values of the types.
+// Values are added at their position, eg. AFTER the value that precedes them, which is either a previous value or one added
+// by an earlier query.  New first value is added BEFORE the first previous value.
+func (b *Builder) AlterTypes(previous map[string][]string) []string {
+	queries := make([]string, 0)
+	for _, e := range b.enums {
+		previousValues, ok := previous[e.name]
+		if !ok {
+			continue
+		}
+
+		for i, value := range e.values {
+			if containsString(previousValues, value) {
+				continue
+			}
+
+			query := fmt.Sprintf("ALTER TYPE %s ADD VALUE IF NOT EXISTS %s", qualifiedName(b.schema, e.name), QuoteLiteral(value))
+			if i > 0 {
+				query += " AFTER " + QuoteLiteral(e.values[i-1])
+			} else {
+				for _, next := range e.values[1:] {
+					if containsString(previousValues, next) {
+						query += " BEFORE " + QuoteLiteral(next)
+						break
+					}
+				}
+			}
+
+			queries = append(queries, query+";")
+		}
+	}
+
+	return queries
+}
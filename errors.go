@@ -1,18 +1,48 @@
 package pgsqlbuilder
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 type BuilderError struct {
-	Op  string
-	Tag string
-	Err error
+	Op    string
+	Field string
+	Tag   string
+	Err   error
 }
 
 func (e *BuilderError) Error() string {
 	return e.Op + ": " + e.Err.Error()
 }
 
+func (e *BuilderError) Unwrap() error {
+	return e.Err
+}
+
 var fieldNameNotFoundError = errors.New("field name not found")
+var nilValueOperatorError = errors.New("nil value can only be used with OpEqual or OpNotEqual")
+var columnOperatorError = errors.New("operator can only be used with an array or a JSONB column")
+var defaultValueError = errors.New("invalid default value")
+var primaryKeyError = errors.New("invalid primary key")
+var noPrimaryKeyError = errors.New("primary key not found")
+var noUpdateColumnsError = errors.New("no columns to update")
+var foreignKeyError = errors.New("invalid foreign key")
+var indexError = errors.New("invalid index")
+var checkError = errors.New("invalid check constraint")
+var enumError = errors.New("invalid enum")
+var generatedColumnError = errors.New("invalid generated column")
+var fieldNotWritableError = errors.New("field is not writable")
+var unsupportedFieldError = errors.New("unsupported field type")
+var unknownTagOptionError = errors.New("unknown tag option")
+var invalidTagValueError = errors.New("invalid tag value")
+var columnNameError = errors.New("invalid column name")
+var duplicateColumnError = errors.New("duplicate column name")
+var conflictTargetError = errors.New("invalid conflict target")
+var conflictActionError = errors.New("invalid conflict action")
+var softDeleteError = errors.New("invalid soft delete field")
+var noSoftDeleteError = errors.New("soft delete field not found")
+var versionError = errors.New("invalid version field")
 
 var getColumnNameBuilderError = func(source string) *BuilderError {
 	return &BuilderError{
@@ -26,3 +56,32 @@ var getClauseBuilderError = func(clause, source string, err error) *BuilderError
 		Err: err,
 	}
 }
+var getNilValueBuilderError = func(field string) *BuilderError {
+	return &BuilderError{
+		Op:  "get condition for " + field + " field",
+		Err: nilValueOperatorError,
+	}
+}
+var getOperatorBuilderError = func(field string) *BuilderError {
+	return &BuilderError{
+		Op:  "get condition for " + field + " field",
+		Err: columnOperatorError,
+	}
+}
+var getTableBuilderError = func(table string, err error) *BuilderError {
+	return &BuilderError{
+		Op:  "build queries for " + table + " table",
+		Err: err,
+	}
+}
+var getTagBuilderError = func(field, tag string, err error) *BuilderError {
+	return &BuilderError{
+		Op:    "parse " + tag + " tag of " + field + " field",
+		Field: field,
+		Tag:   tag,
+		Err:   err,
+	}
+}
+var invalidDefaultValueError = func(value, columnType string) error {
+	return fmt.Errorf("%w %q for %s column", defaultValueError, value, columnType)
+}
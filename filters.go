@@ -1,6 +1,7 @@
 package pgsqlbuilder
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"sort"
@@ -35,7 +36,11 @@ func FiltersInterfaces(filters *Filters) []interface{} {
 	sort.Strings(sorted)
 
 	for _, filter := range sorted {
-		interfaces = append(interfaces, (*filters)[filter].Val)
+		// nil values become IS NULL or IS NOT NULL and do not have a placeholder
+		if isNilValue((*filters)[filter].Val) {
+			continue
+		}
+		interfaces = append(interfaces, filterInterface((*filters)[filter].Val))
 	}
 
 	// Get pointers to values from raw query
@@ -164,7 +169,14 @@ func FiltersInterfaces(filters *Filters) []interface{} {
 	return interfaces
 }
 
+// SetObjFields sets fields of the object to values from the filters.  Fields are resolved with the default sql tag,
+// and Builder has a method of the same name for its TagName.
 func SetObjFields(obj interface{}, values *Filters) error {
+	return setObjFieldsWithTag(obj, values, DefaultTagName)
+}
+
+// setObjFieldsWithTag sets fields of the object, which are resolved with the specified tag.
+func setObjFieldsWithTag(obj interface{}, values *Filters, tagName string) error {
 	if values == nil || len(*values) == 0 {
 		return nil
 	}
@@ -185,43 +197,49 @@ func SetObjFields(obj interface{}, values *Filters) error {
 
 	var firstErr error
 	typ := objValue.Type()
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
+	for _, sf := range structFields(typ, tagName) {
+		field := sf.field
 
 		if field.PkgPath != "" { // PkgPath is non‑empty for unexported fields
 			continue
 		}
 
-		if !IsFieldKindSupported(field.Type.Kind()) {
-			continue
-		}
-
-		value, ok := (*values)[field.Name]
+		value, ok := (*values)[sf.name]
 		if !ok {
 			continue
 		}
 
-		dest := objValue.Field(i)
+		dest := objValue.FieldByIndex(sf.index)
 
-		// TODO: We do not support pointers at this stage
 		if dest.Kind() == reflect.Ptr {
-			continue
-		}
-
-		inVal := reflect.ValueOf(value.Val)
+			if isNilValue(value.Val) {
+				dest.Set(reflect.Zero(dest.Type()))
+				continue
+			}
 
-		if inVal.Type().AssignableTo(dest.Type()) {
-			dest.Set(inVal)
-			continue
-		}
+			inVal := reflect.ValueOf(value.Val)
+			if inVal.Type().AssignableTo(dest.Type()) {
+				dest.Set(inVal)
+				continue
+			}
 
-		if inVal.Type().ConvertibleTo(dest.Type()) {
-			dest.Set(inVal.Convert(dest.Type()))
-			continue
+			elemVal, ok := convertValue(reflect.Indirect(inVal), dest.Type().Elem())
+			if ok {
+				ptr := reflect.New(dest.Type().Elem())
+				ptr.Elem().Set(elemVal)
+				dest.Set(ptr)
+				continue
+			}
+		} else if !isNilValue(value.Val) {
+			destVal, ok := convertValue(reflect.Indirect(reflect.ValueOf(value.Val)), dest.Type())
+			if ok {
+				dest.Set(destVal)
+				continue
+			}
 		}
 
 		err := fmt.Errorf("cannot set field %s (%s) with value of type %T",
-			field.Name, dest.Type(), value)
+			sf.name, dest.Type(), value.Val)
 		if firstErr == nil {
 			firstErr = err
 		}
@@ -229,3 +247,63 @@ func SetObjFields(obj interface{}, values *Filters) error {
 
 	return firstErr
 }
+
+// convertValue returns inVal as a value of type t, if possible.
+func convertValue(inVal reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if inVal.Type().AssignableTo(t) {
+		return inVal, true
+	}
+
+	if inVal.Type().ConvertibleTo(t) {
+		return inVal.Convert(t), true
+	}
+
+	// Timestamps can be passed as RFC3339 strings
+	if t == timeType && inVal.Kind() == reflect.String {
+		v, ok := timeFromString(inVal.String())
+		if ok {
+			return reflect.ValueOf(v), true
+		}
+	}
+
+	// JSONB values can be passed as JSON strings
+	if isJSONType(t) {
+		var data []byte
+		if inVal.Kind() == reflect.String {
+			data = []byte(inVal.String())
+		} else if inVal.Kind() == reflect.Slice && inVal.Type().Elem().Kind() == reflect.Uint8 {
+			data = inVal.Bytes()
+		}
+
+		v := reflect.New(t)
+		if data != nil && json.Unmarshal(data, v.Interface()) == nil {
+			return v.Elem(), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// isNilValue checks if a filter value is nil, either untyped or a typed nil pointer.
+// filterInterface returns a query argument for a filter value.  Maps and structs are wrapped with JSONB, so that they are
+// marshaled with encoding/json as database/sql does not convert them.
+func filterInterface(val interface{}) interface{} {
+	if isJSONType(reflect.TypeOf(val)) {
+		return JSONB{Val: val}
+	}
+
+	return val
+}
+
+func isNilValue(val interface{}) bool {
+	if val == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(val)
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		return v.IsNil()
+	}
+
+	return false
+}
@@ -0,0 +1,115 @@
+package pgsqlbuilder
+
+import (
+	"testing"
+	"time"
+)
+
+type setObjFieldsStruct struct {
+	Name      string
+	Age       int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func TestSetObjFields(t *testing.T) {
+	obj := &setObjFieldsStruct{}
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	err := SetObjFields(obj, &Filters{
+		"Name":      {Val: "John"},
+		"Age":       {Val: int64(33)},
+		"CreatedAt": {Val: createdAt},
+		"UpdatedAt": {Val: "2024-01-03T03:04:05Z"},
+	})
+	if err != nil {
+		t.Fatalf("SetObjFields failed: %v", err)
+	}
+
+	if obj.Name != "John" || obj.Age != 33 || !obj.CreatedAt.Equal(createdAt) {
+		t.Fatalf("Fields set incorrectly: %+v", obj)
+	}
+	if !obj.UpdatedAt.Equal(createdAt.Add(24 * time.Hour)) {
+		t.Fatalf("Timestamp string parsed incorrectly: %v", obj.UpdatedAt)
+	}
+}
+
+type setObjPointerFieldsStruct struct {
+	Nickname  *string
+	Score     *int64
+	DeletedAt *time.Time
+}
+
+func TestSetObjPointerFields(t *testing.T) {
+	nickname := "Johnny"
+	obj := &setObjPointerFieldsStruct{DeletedAt: &time.Time{}}
+
+	err := SetObjFields(obj, &Filters{
+		"Nickname":  {Val: &nickname},
+		"Score":     {Val: 7},
+		"DeletedAt": {Val: nil},
+	})
+	if err != nil {
+		t.Fatalf("SetObjFields failed: %v", err)
+	}
+
+	if obj.Nickname == nil || *obj.Nickname != "Johnny" {
+		t.Fatalf("Nickname set incorrectly: %v", obj.Nickname)
+	}
+	if obj.Score == nil || *obj.Score != 7 {
+		t.Fatalf("Score set incorrectly: %v", obj.Score)
+	}
+	if obj.DeletedAt != nil {
+		t.Fatalf("DeletedAt should be nil: %v", obj.DeletedAt)
+	}
+}
+
+type setObjJSONFieldsStruct struct {
+	Settings map[string]string
+	Items    []setObjFieldsStruct
+}
+
+func TestSetObjJSONFields(t *testing.T) {
+	obj := &setObjJSONFieldsStruct{}
+
+	err := SetObjFields(obj, &Filters{
+		"Settings": {Val: `{"theme":"dark"}`},
+		"Items":    {Val: []byte(`[{"Name":"John"}]`)},
+	})
+	if err != nil {
+		t.Fatalf("SetObjFields failed: %v", err)
+	}
+
+	if obj.Settings["theme"] != "dark" || len(obj.Items) != 1 || obj.Items[0].Name != "John" {
+		t.Fatalf("JSON fields set incorrectly: %+v", obj)
+	}
+}
+
+type setObjEmbeddedBase struct {
+	CreatedBy int64
+}
+
+type setObjEmbeddedAddress struct {
+	City string
+}
+
+type setObjEmbeddedFieldsStruct struct {
+	setObjEmbeddedBase
+	Address setObjEmbeddedAddress `sql:"prefix:address_"`
+}
+
+func TestSetObjEmbeddedFields(t *testing.T) {
+	obj := &setObjEmbeddedFieldsStruct{}
+
+	err := SetObjFields(obj, &Filters{
+		"CreatedBy":    {Val: 5},
+		"Address.City": {Val: "Oslo"},
+	})
+	if err != nil {
+		t.Fatalf("SetObjFields failed: %v", err)
+	}
+
+	if obj.CreatedBy != 5 || obj.Address.City != "Oslo" {
+		t.Fatalf("Embedded fields set incorrectly: %+v", obj)
+	}
+}
@@ -0,0 +1,66 @@
+package pgsqlbuilder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// foreignKey is a reference to another table, declared with fk, ondelete and onupdate tags.
+type foreignKey struct {
+	// reference is a name of Builder from Options.References, or a table with an optional column, eg. user.id
+	reference string
+	onDelete  string
+	onUpdate  string
+}
+
+// foreignKeyDefinition returns REFERENCES clause for a column.
+func (b *Builder) foreignKeyDefinition(fk *foreignKey, isNullable bool) (string, error) {
+	if fk.reference == "" {
+		return "", fmt.Errorf("%w: ondelete and onupdate require fk", foreignKeyError)
+	}
+
+	// Column must be nullable to be set to NULL when the referenced row is removed or changed
+	if !isNullable && (fk.onDelete == "SET NULL" || fk.onUpdate == "SET NULL") {
+		return "", fmt.Errorf("%w: SET NULL requires a pointer field", foreignKeyError)
+	}
+
+	var references string
+
+	// Referenced Builder resolves its table name (with prefix) and its primary key column
+	ref, ok := b.references[fk.reference]
+	if ok {
+		if ref == nil || len(ref.primaryKeyFields) != 1 {
+			return "", fmt.Errorf("%w: %s must have a single column primary key", foreignKeyError, fk.reference)
+		}
+		references = fmt.Sprintf("REFERENCES %s(%s)", qualifiedName(b.referenceSchema(ref.schema), ref.tableBaseName), ref.primaryKeyColumn)
+	} else {
+		table, column, hasColumn := strings.Cut(fk.reference, ".")
+		if table == "" || strings.Contains(column, ".") {
+			return "", fmt.Errorf("%w: invalid reference %s", foreignKeyError, fk.reference)
+		}
+
+		references = "REFERENCES " + qualifiedName(b.schema, table)
+		if hasColumn {
+			references += "(" + quoteIdentifier(column) + ")"
+		}
+	}
+
+	if fk.onDelete != "" {
+		references += " ON DELETE " + fk.onDelete
+	}
+	if fk.onUpdate != "" {
+		references += " ON UPDATE " + fk.onUpdate
+	}
+
+	return references, nil
+}
+
+// referenceSchema returns a schema of the referenced table.  Table without a schema is in the same schema as the
+// referencing one, so that references stay within a tenant schema when the builder is cloned with WithSchema.
+func (b *Builder) referenceSchema(schema string) string {
+	if schema != "" {
+		return schema
+	}
+
+	return b.schema
+}
@@ -1,12 +1,17 @@
 package pgsqlbuilder
 
 import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
 	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
+	"unicode/utf8"
 )
 
 var intRegex = regexp.MustCompile(`^-?\d+$`)
@@ -28,21 +33,87 @@ func IsFieldKindSupported(k reflect.Kind) bool {
 	}
 }
 
+// isIntegerKind checks if a specific reflect kind is a signed or unsigned integer.
+func isIntegerKind(k reflect.Kind) bool {
+	return isNumericKind(k) && k != reflect.Float32 && k != reflect.Float64
+}
+
+// isNumericKind checks if a specific reflect kind is an integer or a floating-point number.
+func isNumericKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFieldTypeSupported checks if a specific reflect type of the field is supported by the Builder.
+// Apart from the kinds accepted by IsFieldKindSupported, time.Time, pointers to supported types and slices of them are supported as well.
+// Maps and structs are supported as JSONB columns.
+func IsFieldTypeSupported(t reflect.Type) bool {
+	// Pointers are supported as nullable columns, as long as they point to a supported type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		if t.Kind() == reflect.Ptr {
+			return false
+		}
+	}
+
+	// Slices are supported as arrays, as long as their items are of a supported basic type
+	if t.Kind() == reflect.Slice && !isJSONType(t) {
+		t = t.Elem()
+		if t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+			return false
+		}
+	}
+
+	if t == timeType || isJSONType(t) {
+		return true
+	}
+
+	return IsFieldKindSupported(t.Kind())
+}
+
+// isJSONType checks if a type is stored as JSONB, which is the case for maps, structs (except time.Time) and slices of them.
+// Types converted by the database driver, such as sql.NullString, are not.
+func isJSONType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	if t == timeType || isValuerType(t) {
+		return false
+	}
+
+	return t.Kind() == reflect.Map || t.Kind() == reflect.Struct
+}
+
+// isValuerType checks if a type, or a pointer to it, implements driver.Valuer or sql.Scanner.
+func isValuerType(t reflect.Type) bool {
+	ptrType := reflect.PointerTo(t)
+	return t.Implements(valuerType) || t.Implements(scannerType) || ptrType.Implements(valuerType) || ptrType.Implements(scannerType)
+}
+
 // IsStructField checks if a field exists in a struct.
+// Fields of embedded structs are promoted, and fields of nested structs with prefix tag are named with a path, eg. Address.City.
+// Fields are resolved with the default sql tag, and Builder has a method of the same name for its TagName.
 func IsStructField(u interface{}, field string) bool {
+	return isStructFieldWithTag(u, field, DefaultTagName)
+}
+
+// isStructFieldWithTag checks if a field exists in a struct, resolving fields with the specified tag.
+func isStructFieldWithTag(u interface{}, field string, tagName string) bool {
 	v := reflect.ValueOf(u)
 	i := reflect.Indirect(v)
 	s := i.Type()
 
-	for j := 0; j < s.NumField(); j++ {
-		f := s.Field(j)
-		k := f.Type.Kind()
-
-		if !IsFieldKindSupported(k) {
-			continue
-		}
-
-		if f.Name == field {
+	for _, f := range structFields(s, tagName) {
+		if f.name == field {
 			return true
 		}
 	}
@@ -51,7 +122,14 @@ func IsStructField(u interface{}, field string) bool {
 }
 
 // StructFieldValueFromString takes a field value as string and converts it (if possible) to a value type of that field.
+// For pointer fields, "null" is converted to nil, and any other value is converted to the type the field points to.
+// Field tags are read from the default sql tag, and Builder has a method of the same name for its TagName.
 func StructFieldValueFromString(obj interface{}, name string, value string) (bool, interface{}) {
+	return fieldValueFromString(obj, name, value, DefaultTagName)
+}
+
+// fieldValueFromString converts a field value from string, resolving fields and reading their tags with the specified tag.
+func fieldValueFromString(obj interface{}, name string, value string, tagName string) (bool, interface{}) {
 	objValue := reflect.ValueOf(obj)
 	objIndirect := reflect.Indirect(objValue)
 	objType := objIndirect.Type()
@@ -60,47 +138,159 @@ func StructFieldValueFromString(obj interface{}, name string, value string) (boo
 		objType = reflect.ValueOf(obj.(reflect.Value).Interface()).Type().Elem()
 	}
 
-	for j := 0; j < objType.NumField(); j++ {
-		field := objType.Field(j)
-		kind := field.Type.Kind()
-
-		if !IsFieldKindSupported(kind) {
+	for _, sf := range structFields(objType, tagName) {
+		if sf.name != name {
 			continue
 		}
 
-		if field.Name == name {
-			switch kind {
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-				if intRegex.MatchString(value) {
-					v, err := strconv.ParseInt(value, 10, 64)
-					if err == nil {
-						return true, v
-					}
-				}
-			case reflect.Float32, reflect.Float64:
-				if floatRegex.MatchString(value) || intRegex.MatchString(value) {
-					v, err := strconv.ParseFloat(value, 64)
-					if err == nil {
-						return true, v
-					}
-				}
-			case reflect.Bool:
-				if strings.ToLower(value) == "true" || strings.ToLower(value) == "false" {
-					v, err := strconv.ParseBool(value)
-					if err == nil {
-						return true, v
-					}
-				}
-			case reflect.String:
-				return true, value
+		field := sf.field
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			if strings.ToLower(value) == "null" {
+				return true, nil
 			}
+
+			fieldType = fieldType.Elem()
+		}
+
+		// Value for NUMERIC(p,s) column must fit its precision and scale
+		if !numericValueFitsTag(field.Tag.Get(tagName), value) {
 			return false, nil
 		}
+
+		return valueFromString(fieldType, value)
+	}
+
+	return false, nil
+}
+
+// valueFromString converts a string to a value of the specified field type.
+func valueFromString(fieldType reflect.Type, value string) (bool, interface{}) {
+	if fieldType == timeType {
+		v, ok := timeFromString(value)
+		if ok {
+			return true, v
+		}
+		return false, nil
+	}
+
+	// JSONB value must be a valid JSON, and it is returned as a string
+	if isJSONType(fieldType) {
+		if json.Valid([]byte(value)) {
+			return true, value
+		}
+		return false, nil
+	}
+
+	// Array is a comma-separated list of items, optionally wrapped with curly brackets
+	if fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() != reflect.Uint8 {
+		value = strings.TrimSuffix(strings.TrimPrefix(value, "{"), "}")
+
+		items := reflect.MakeSlice(fieldType, 0, 0)
+		if value == "" {
+			return true, items.Interface()
+		}
+
+		for _, item := range strings.Split(value, ",") {
+			if fieldType.Elem().Kind() != reflect.String {
+				item = strings.TrimSpace(item)
+			}
+
+			ok, v := valueFromString(fieldType.Elem(), item)
+			if !ok {
+				return false, nil
+			}
+			items = reflect.Append(items, reflect.ValueOf(v).Convert(fieldType.Elem()))
+		}
+
+		return true, items.Interface()
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if intRegex.MatchString(value) {
+			v, err := strconv.ParseInt(value, 10, 64)
+			if err == nil {
+				return true, v
+			}
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if intRegex.MatchString(value) {
+			v, err := strconv.ParseUint(value, 10, 64)
+			if err == nil {
+				return true, v
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		if floatRegex.MatchString(value) || intRegex.MatchString(value) {
+			v, err := strconv.ParseFloat(value, 64)
+			if err == nil {
+				return true, v
+			}
+		}
+	case reflect.Bool:
+		if strings.ToLower(value) == "true" || strings.ToLower(value) == "false" {
+			v, err := strconv.ParseBool(value)
+			if err == nil {
+				return true, v
+			}
+		}
+	case reflect.String:
+		return true, value
 	}
 
 	return false, nil
 }
 
+// numericValueFitsTag checks if a value is a valid number for the NUMERIC type set in the tag.
+// It returns true when the tag does not contain a NUMERIC type.
+func numericValueFitsTag(tag string, value string) bool {
+	columnType := ""
+	for _, opt := range strings.Split(tag, " ") {
+		if strings.HasPrefix(opt, "type:") {
+			columnType = strings.ToUpper(strings.TrimPrefix(opt, "type:"))
+		}
+	}
+
+	typeMatch := regexpNumericType.FindStringSubmatch(columnType)
+	if typeMatch == nil {
+		return true
+	}
+
+	valueMatch := regexpNumericValue.FindStringSubmatch(value)
+	if valueMatch == nil {
+		return false
+	}
+
+	// NUMERIC without precision accepts any number
+	if typeMatch[3] == "" {
+		return true
+	}
+
+	precision, _ := strconv.Atoi(typeMatch[3])
+	scale, _ := strconv.Atoi(typeMatch[5])
+
+	integerDigits := len(strings.TrimLeft(valueMatch[1], "0"))
+	fractionDigits := len(strings.TrimRight(valueMatch[3], "0"))
+
+	return integerDigits <= precision-scale && fractionDigits <= scale
+}
+
+// timeFromString parses RFC3339 timestamps and, as a fallback, plain dates.
+func timeFromString(value string) (time.Time, bool) {
+	v, err := time.Parse(time.RFC3339Nano, value)
+	if err == nil {
+		return v, true
+	}
+
+	v, err = time.Parse(time.DateOnly, value)
+	if err == nil {
+		return v, true
+	}
+
+	return time.Time{}, false
+}
+
 // FieldToColumn converts struct field name to database column name.
 func FieldToColumn(s string) string {
 	if s == "ID" {
@@ -151,6 +341,96 @@ func ColumnToField(s string) string {
 	return result
 }
 
+// containsString checks if a list contains a string.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitTag splits tag into options separated with spaces.
+// Spaces inside parentheses and single quotes do not split, eg. where:(.Age > 18) is a single option.
+func splitTag(tag string) []string {
+	opts := make([]string, 0)
+
+	depth := 0
+	quoted := false
+	opt := ""
+	for _, ch := range tag {
+		switch {
+		case ch == '\'':
+			quoted = !quoted
+		case ch == '(' && !quoted:
+			depth++
+		case ch == ')' && !quoted && depth > 0:
+			depth--
+		case ch == ' ' && !quoted && depth == 0:
+			if opt != "" {
+				opts = append(opts, opt)
+			}
+			opt = ""
+			continue
+		}
+
+		opt += string(ch)
+	}
+
+	if opt != "" {
+		opts = append(opts, opt)
+	}
+
+	return opts
+}
+
+// quoteIdentifier returns a name as a quoted SQL identifier, with double quotes doubled.
+func quoteIdentifier(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// shortIdentifier returns a name that fits PostgreSQL identifier length of 63 bytes.  Longer name is truncated and ends
+// with a hash of the whole name, so that truncated names do not collide.
+func shortIdentifier(s string) string {
+	if len(s) <= maxIdentifierLength {
+		return s
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	suffix := fmt.Sprintf("_%08x", h.Sum32())
+
+	// Name is cut at a character boundary
+	prefix := s[:maxIdentifierLength-len(suffix)]
+	for !utf8.ValidString(prefix) {
+		prefix = prefix[:len(prefix)-1]
+	}
+
+	return prefix + suffix
+}
+
+// qualifiedName returns a quoted name of a database object, qualified with a schema when it is not empty.
+func qualifiedName(schema string, name string) string {
+	if schema == "" {
+		return quoteIdentifier(name)
+	}
+
+	return quoteIdentifier(schema) + "." + quoteIdentifier(name)
+}
+
+// QuoteLiteral returns a string as a safely escaped SQL literal, with single quotes doubled.
+// Backslashes are escaped too, using the E prefix, so the result does not depend on standard_conforming_strings.
+func QuoteLiteral(s string) string {
+	s = strings.ReplaceAll(s, "'", "''")
+	if strings.Contains(s, `\`) {
+		return "E'" + strings.ReplaceAll(s, `\`, `\\`) + "'"
+	}
+
+	return "'" + s + "'"
+}
+
 // PrettifyCreateTable prettifies SQL query to make it more human-readable.
 func PrettifyCreateTable(sql string) string {
 	sql = strings.Replace(sql, "(", "(\n  ", 1)
@@ -1,6 +1,10 @@
 package pgsqlbuilder
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+	"time"
+)
 
 type structFieldValueFromString struct {
 	Int64Field int64
@@ -18,3 +22,161 @@ func TestStructFieldValueFromString(t *testing.T) {
 		t.Fatal("Parsed value is invalid")
 	}
 }
+
+type structFieldTimeValueFromString struct {
+	CreatedAt time.Time
+}
+
+func TestStructFieldTimeValueFromString(t *testing.T) {
+	testObj := &structFieldTimeValueFromString{}
+
+	ok, value := StructFieldValueFromString(testObj, "CreatedAt", "2024-02-29T13:14:15Z")
+	if !ok {
+		t.Fatal("Failed to parse RFC3339 value")
+	}
+
+	want := time.Date(2024, 2, 29, 13, 14, 15, 0, time.UTC)
+	if !value.(time.Time).Equal(want) {
+		t.Fatalf("Parsed value is invalid: %v", value)
+	}
+
+	ok, _ = StructFieldValueFromString(testObj, "CreatedAt", "yesterday")
+	if ok {
+		t.Fatal("Invalid timestamp should not be parsed")
+	}
+}
+
+type structFieldPointerValueFromString struct {
+	Score *int64
+}
+
+func TestStructFieldPointerValueFromString(t *testing.T) {
+	testObj := &structFieldPointerValueFromString{}
+
+	ok, value := StructFieldValueFromString(testObj, "Score", "12")
+	if !ok || value.(int64) != 12 {
+		t.Fatalf("Failed to parse pointer field value: %v", value)
+	}
+
+	ok, value = StructFieldValueFromString(testObj, "Score", "null")
+	if !ok || value != nil {
+		t.Fatalf("Failed to parse null pointer field value: %v", value)
+	}
+}
+
+type structFieldNumericValueFromString struct {
+	Balance string  `sql:"type:numeric(6,2)"`
+	Price   float64 `sql:"type:numeric(6,2)"`
+}
+
+func TestStructFieldNumericValueFromString(t *testing.T) {
+	testObj := &structFieldNumericValueFromString{}
+
+	ok, value := StructFieldValueFromString(testObj, "Balance", "1234.50")
+	if !ok || value.(string) != "1234.50" {
+		t.Fatalf("Failed to parse numeric string value: %v", value)
+	}
+
+	ok, value = StructFieldValueFromString(testObj, "Price", "-0.99")
+	if !ok || value.(float64) != -0.99 {
+		t.Fatalf("Failed to parse numeric float value: %v", value)
+	}
+
+	for _, invalid := range []string{"12345.5", "1.234", "abc", "1e5"} {
+		ok, _ = StructFieldValueFromString(testObj, "Balance", invalid)
+		if ok {
+			t.Fatalf("Value %s should not fit NUMERIC(6,2)", invalid)
+		}
+	}
+}
+
+type structFieldCustomTagValueFromString struct {
+	ID      int64
+	Balance string                    `db:"type:numeric(6,2)"`
+	Price   structFieldEmbeddedNested `db:"prefix:price_"`
+	Secret  string                    `db:"-"`
+}
+
+func TestBuilderStructFieldValueFromString(t *testing.T) {
+	testObj := &structFieldCustomTagValueFromString{}
+	h := New(testObj, Options{TagName: "db"})
+
+	ok, value := h.StructFieldValueFromString(testObj, "Balance", "1234.50")
+	if !ok || value.(string) != "1234.50" {
+		t.Fatalf("Failed to parse numeric string value: %v", value)
+	}
+
+	ok, _ = h.StructFieldValueFromString(testObj, "Balance", "12345.5")
+	if ok {
+		t.Fatal("Value should be checked against NUMERIC type from the custom tag")
+	}
+
+	if !h.IsStructField(testObj, "Price.Amount") || h.IsStructField(testObj, "Price") || h.IsStructField(testObj, "Secret") {
+		t.Fatal("Fields should be resolved with the custom tag")
+	}
+
+	ok, _ = h.StructFieldValueFromString(testObj, "Price.Amount", "1.5")
+	if !ok {
+		t.Fatal("Failed to parse nested field value")
+	}
+
+	err := h.SetObjFields(testObj, &Filters{"Price.Amount": {Val: "1.5"}, "Secret": {Val: "x"}})
+	if err != nil || testObj.Price.Amount != "1.5" || testObj.Secret != "" {
+		t.Fatalf("Failed to set fields resolved with the custom tag: %v %v", err, testObj)
+	}
+}
+
+type structFieldArrayValueFromString struct {
+	Tags   []string
+	Levels []int32
+}
+
+func TestStructFieldArrayValueFromString(t *testing.T) {
+	testObj := &structFieldArrayValueFromString{}
+
+	ok, value := StructFieldValueFromString(testObj, "Levels", "{1, 2,3}")
+	if !ok || !reflect.DeepEqual(value, []int32{1, 2, 3}) {
+		t.Fatalf("Failed to parse int array value: %v", value)
+	}
+
+	ok, value = StructFieldValueFromString(testObj, "Tags", "a,b")
+	if !ok || !reflect.DeepEqual(value, []string{"a", "b"}) {
+		t.Fatalf("Failed to parse string array value: %v", value)
+	}
+
+	ok, _ = StructFieldValueFromString(testObj, "Levels", "1,x")
+	if ok {
+		t.Fatal("Invalid array item should not be parsed")
+	}
+}
+
+type structFieldEmbeddedBase struct {
+	CreatedAt time.Time
+}
+
+type structFieldEmbeddedNested struct {
+	Amount string `sql:"type:NUMERIC(5,2)"`
+}
+
+type structFieldEmbeddedValueFromString struct {
+	structFieldEmbeddedBase
+	Price structFieldEmbeddedNested `sql:"prefix:price_"`
+}
+
+func TestStructFieldEmbeddedValueFromString(t *testing.T) {
+	testObj := &structFieldEmbeddedValueFromString{}
+
+	if !IsStructField(testObj, "CreatedAt") || !IsStructField(testObj, "Price.Amount") || IsStructField(testObj, "Price") {
+		t.Fatal("Fields of embedded and nested structs should be promoted")
+	}
+
+	ok, value := StructFieldValueFromString(testObj, "CreatedAt", "2024-01-02")
+	if !ok || !value.(time.Time).Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Failed to parse embedded field value: %v", value)
+	}
+
+	ok, _ = StructFieldValueFromString(testObj, "Price.Amount", "1234.5")
+	if ok {
+		t.Fatal("Value of nested field should be checked against its NUMERIC type")
+	}
+}
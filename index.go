@@ -0,0 +1,156 @@
+package pgsqlbuilder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// index is declared with index, using and where tags.
+type index struct {
+	// name is a group name for composite index, or a field name
+	name    string
+	isGroup bool
+	fields  []string
+	method  string
+	where   string
+	columns []string
+}
+
+// setFieldIndex adds the field to its own index, or to a named group (composite index).
+func (b *Builder) setFieldIndex(fieldName string, group string) {
+	name := group
+	if name == "" {
+		name = fieldName
+	}
+
+	for _, idx := range b.indexes {
+		if idx.name != name || idx.isGroup != (group != "") {
+			continue
+		}
+
+		if idx.isGroup {
+			idx.fields = append(idx.fields, fieldName)
+		}
+		return
+	}
+
+	b.indexes = append(b.indexes, &index{name: name, isGroup: group != "", fields: []string{fieldName}})
+}
+
+// reflectIndexes resolves columns, method and condition of indexes once all the columns are known.
+func (b *Builder) reflectIndexes() {
+	for _, idx := range b.indexes {
+		idx.columns = make([]string, 0, len(idx.fields))
+
+		for _, fieldName := range idx.fields {
+			idx.columns = append(idx.columns, fmt.Sprintf(`"%s"`, b.fieldColumnName[fieldName]))
+
+			err := b.setIndexFromField(idx, fieldName)
+			if err != nil {
+				b.addError(getTagBuilderError(fieldName, b.tagName, err))
+			}
+		}
+	}
+
+	for fieldName := range b.fieldIndexMethod {
+		if !b.isFieldIndexed(fieldName) {
+			b.addError(getTagBuilderError(fieldName, b.tagName, fmt.Errorf("%w: using requires index", indexError)))
+		}
+	}
+	for fieldName := range b.fieldIndexWhere {
+		if !b.isFieldIndexed(fieldName) {
+			b.addError(getTagBuilderError(fieldName, b.tagName, fmt.Errorf("%w: where requires index", indexError)))
+		}
+	}
+}
+
+// setIndexFromField sets index method and condition from the tags of a field in the index.
+func (b *Builder) setIndexFromField(idx *index, fieldName string) error {
+	method, ok := b.fieldIndexMethod[fieldName]
+	if ok {
+		if !indexMethods[method] {
+			return fmt.Errorf("%w: unknown method %s", indexError, method)
+		}
+		if idx.method != "" && idx.method != method {
+			return fmt.Errorf("%w: conflicting methods in %s", indexError, idx.name)
+		}
+		idx.method = method
+	}
+
+	where, ok := b.fieldIndexWhere[fieldName]
+	if !ok {
+		return nil
+	}
+
+	where, err := b.resolveFields(where, "index condition")
+	if err != nil {
+		return err
+	}
+	if idx.where != "" && idx.where != where {
+		return fmt.Errorf("%w: conflicting conditions in %s", indexError, idx.name)
+	}
+	idx.where = where
+
+	return nil
+}
+
+func (b *Builder) isFieldIndexed(fieldName string) bool {
+	for _, idx := range b.indexes {
+		for _, indexField := range idx.fields {
+			if indexField == fieldName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// indexName returns a name of the index, eg. product_name_idx.  Name longer than 63 bytes is truncated with a hash suffix.
+func (b *Builder) indexName(idx *index) string {
+	name := idx.name
+	if !idx.isGroup {
+		name = b.fieldColumnName[idx.name]
+	}
+
+	return quoteIdentifier(shortIdentifier(b.tableBaseName + "_" + name + "_idx"))
+}
+
+// qualifiedIndexName returns a name of the index qualified with the schema of the table.
+// Index is always created in the schema of its table, so only DROP INDEX needs it.
+func (b *Builder) qualifiedIndexName(idx *index) string {
+	if b.schema == "" {
+		return b.indexName(idx)
+	}
+
+	return quoteIdentifier(b.schema) + "." + b.indexName(idx)
+}
+
+// CreateIndexes returns SQL queries for creating indexes declared with the index tag.
+func (b *Builder) CreateIndexes() []string {
+	queries := make([]string, 0, len(b.indexes))
+	for _, idx := range b.indexes {
+		query := fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s", b.indexName(idx), b.tableName)
+		if idx.method != "" {
+			query += " USING " + idx.method
+		}
+		query += fmt.Sprintf(" (%s)", strings.Join(idx.columns, ","))
+		if idx.where != "" {
+			query += " WHERE " + idx.where
+		}
+
+		queries = append(queries, query+";")
+	}
+
+	return queries
+}
+
+// DropIndexes returns SQL queries for dropping indexes declared with the index tag.
+func (b *Builder) DropIndexes() []string {
+	queries := make([]string, 0, len(b.indexes))
+	for _, idx := range b.indexes {
+		queries = append(queries, fmt.Sprintf("DROP INDEX IF EXISTS %s;", b.qualifiedIndexName(idx)))
+	}
+
+	return queries
+}
@@ -0,0 +1,41 @@
+package pgsqlbuilder
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// JSONB wraps a value of a JSONB column.  It is marshaled with encoding/json when written to the database, and
+// unmarshaled when scanned from it, in which case Val must be a pointer, eg. &obj.Settings.
+type JSONB struct {
+	Val interface{}
+}
+
+// Value implements driver.Valuer interface.
+func (j JSONB) Value() (driver.Value, error) {
+	if isNilValue(j.Val) {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(j.Val)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner interface.
+func (j *JSONB) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, j.Val)
+	case string:
+		return json.Unmarshal([]byte(v), j.Val)
+	default:
+		return fmt.Errorf("cannot scan %T into JSONB", src)
+	}
+}
@@ -0,0 +1,16 @@
+package pgsqlbuilder
+
+import "testing"
+
+func TestJSONB(t *testing.T) {
+	value, err := JSONB{Val: map[string]string{"theme": "dark"}}.Value()
+	if err != nil || value.(string) != `{"theme":"dark"}` {
+		t.Fatalf("Failed to marshal value: %v %v", value, err)
+	}
+
+	settings := map[string]string{}
+	err = (&JSONB{Val: &settings}).Scan([]byte(`{"theme":"light"}`))
+	if err != nil || settings["theme"] != "light" {
+		t.Fatalf("Failed to unmarshal value: %v %v", settings, err)
+	}
+}
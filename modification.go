@@ -0,0 +1,86 @@
+package pgsqlbuilder
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// setModificationFields makes the database set CreatedAt and ModifiedAt fields, and prevents CreatedAt and CreatedBy
+// from being updated.  Timestamps are stored as time or as seconds since epoch in integer fields.
+func (b *Builder) setModificationFields() {
+	for _, fieldName := range b.fieldNames {
+		if fieldName != "CreatedAt" && fieldName != "CreatedBy" && fieldName != "ModifiedAt" {
+			continue
+		}
+
+		// Type is not known when reflecting the tags stopped at an earlier field
+		fieldType, ok := b.fieldTypes[fieldName]
+		if !ok {
+			continue
+		}
+
+		if fieldName != "CreatedBy" {
+			switch {
+			case fieldType == timeType:
+				b.fieldAutoValue[fieldName] = "now()"
+			case isIntegerKind(fieldType.Kind()):
+				b.fieldAutoValue[fieldName] = "CAST(EXTRACT(EPOCH FROM now()) AS BIGINT)"
+			default:
+				continue
+			}
+		}
+
+		if fieldName != "ModifiedAt" && b.fieldFlags[fieldName]&FieldFlagInsertOnly == 0 {
+			b.fieldFlags[fieldName] += FieldFlagInsertOnly
+		}
+	}
+}
+
+// autoValueColumns returns columns with values set by the database, and the values.
+// On update, insert-only columns such as CreatedAt are not there.
+func (b *Builder) autoValueColumns(isUpdate bool) ([]string, []string) {
+	columns := make([]string, 0, len(b.fieldAutoValue))
+	values := make([]string, 0, len(b.fieldAutoValue))
+	for _, fieldName := range b.fieldNames {
+		value, ok := b.fieldAutoValue[fieldName]
+		if !ok || (isUpdate && b.fieldFlags[fieldName]&FieldFlagInsertOnly > 0) {
+			continue
+		}
+
+		columns = append(columns, fmt.Sprintf(`"%s"`, b.fieldColumnName[fieldName]))
+		values = append(values, value)
+	}
+
+	return columns, values
+}
+
+// autoValueSet returns a list of columns set to their values by the database on update, eg. "modified_at"=now().
+// When excluded is true, they are set to the inserted values instead, in ON CONFLICT DO UPDATE.
+func (b *Builder) autoValueSet(excluded bool) string {
+	columns, values := b.autoValueColumns(true)
+
+	set := make([]string, 0, len(columns))
+	for i, column := range columns {
+		if excluded {
+			set = append(set, fmt.Sprintf("%s=EXCLUDED.%s", column, column))
+		} else {
+			set = append(set, fmt.Sprintf("%s=%s", column, values[i]))
+		}
+	}
+
+	return strings.Join(set, ",")
+}
+
+// isFieldModification checks if a field is one of CreatedAt, CreatedBy, ModifiedAt and ModifiedBy.
+// Actors are int64 fields, and timestamps are int64 or time.Time fields.
+func (b *Builder) isFieldModification(name string, fieldType reflect.Type) bool {
+	switch name {
+	case "CreatedAt", "ModifiedAt":
+		return fieldType.Kind() == reflect.Int64 || fieldType == timeType
+	case "CreatedBy", "ModifiedBy":
+		return fieldType.Kind() == reflect.Int64
+	default:
+		return false
+	}
+}
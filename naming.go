@@ -0,0 +1,120 @@
+package pgsqlbuilder
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Naming converts struct and field names to table and column names.  It is passed to Builder with Naming option.
+type Naming interface {
+	TableName(structName string) string
+	ColumnName(fieldName string) string
+}
+
+// tableNamer is implemented by structs that set their table name with TableName method.
+type tableNamer interface {
+	TableName() string
+}
+
+// DefaultNaming is the naming used when no other is set in options.  Names are converted with FieldToColumn, and
+// when struct name contains an underscore, eg. User_Register, only the part before it is used as the table name.
+type DefaultNaming struct{}
+
+// TableName converts the part of struct name before an underscore with FieldToColumn.
+func (n DefaultNaming) TableName(structName string) string {
+	structName, _, _ = strings.Cut(structName, "_")
+	return FieldToColumn(structName)
+}
+
+// ColumnName converts field name with FieldToColumn.
+func (n DefaultNaming) ColumnName(fieldName string) string {
+	return FieldToColumn(fieldName)
+}
+
+// SnakeCaseNaming converts names to snake_case, keeping acronyms as single words, eg. HTTPServer becomes http_server.
+type SnakeCaseNaming struct{}
+
+// TableName converts struct name to snake_case.
+func (n SnakeCaseNaming) TableName(structName string) string {
+	return toSnakeCase(structName)
+}
+
+// ColumnName converts field name to snake_case.
+func (n SnakeCaseNaming) ColumnName(fieldName string) string {
+	return toSnakeCase(fieldName)
+}
+
+// PluralNaming pluralizes the last word of table names, eg. UserCategory becomes user_categories.
+// Names are converted with Naming, and SnakeCaseNaming when it is nil.
+type PluralNaming struct {
+	Naming Naming
+}
+
+// TableName converts struct name with Naming and pluralizes its last word.
+func (n PluralNaming) TableName(structName string) string {
+	return pluralize(n.naming().TableName(structName))
+}
+
+// ColumnName converts field name with Naming.
+func (n PluralNaming) ColumnName(fieldName string) string {
+	return n.naming().ColumnName(fieldName)
+}
+
+func (n PluralNaming) naming() Naming {
+	if n.Naming == nil {
+		return SnakeCaseNaming{}
+	}
+
+	return n.Naming
+}
+
+// IdentityNaming uses struct and field names as they are.
+type IdentityNaming struct{}
+
+// TableName returns struct name as it is.
+func (n IdentityNaming) TableName(structName string) string {
+	return structName
+}
+
+// ColumnName returns field name as it is.
+func (n IdentityNaming) ColumnName(fieldName string) string {
+	return fieldName
+}
+
+// toSnakeCase converts a name to snake_case.  Word starts at an uppercase letter that follows a lowercase letter or
+// a digit, or at the last uppercase letter of an acronym, eg. HTTPServer is split into HTTP and Server.
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+
+	o := ""
+	for i, ch := range runes {
+		if i > 0 && unicode.IsUpper(ch) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				o += "_"
+			}
+		}
+
+		o += string(unicode.ToLower(ch))
+	}
+
+	return o
+}
+
+// pluralize returns a plural form of the last word in a snake_case name, following basic English rules.
+func pluralize(s string) string {
+	if s == "" {
+		return s
+	}
+
+	switch {
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "z"),
+		strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsRune("aeiou", rune(s[len(s)-2])):
+		return s[:len(s)-1] + "ies"
+	default:
+		return s + "s"
+	}
+}
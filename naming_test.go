@@ -0,0 +1,32 @@
+package pgsqlbuilder
+
+import "testing"
+
+func TestNaming(t *testing.T) {
+	tests := []struct {
+		naming Naming
+		name   string
+		table  string
+		column string
+	}{
+		{DefaultNaming{}, "User_Register", "user", "user__register"},
+		{DefaultNaming{}, "HTTPServer", "h_t_t_p_server", "h_t_t_p_server"},
+		{SnakeCaseNaming{}, "HTTPServer", "http_server", "http_server"},
+		{SnakeCaseNaming{}, "UserID", "user_id", "user_id"},
+		{SnakeCaseNaming{}, "OAuth2Token", "o_auth2_token", "o_auth2_token"},
+		{PluralNaming{}, "UserCategory", "user_categories", "user_category"},
+		{PluralNaming{}, "Address", "addresses", "address"},
+		{PluralNaming{}, "Day", "days", "day"},
+		{PluralNaming{Naming: IdentityNaming{}}, "Box", "Boxes", "Box"},
+		{IdentityNaming{}, "HTTPServer", "HTTPServer", "HTTPServer"},
+	}
+
+	for _, test := range tests {
+		if got := test.naming.TableName(test.name); got != test.table {
+			t.Fatalf("%T table name of %s: want %s, got %s", test.naming, test.name, test.table, got)
+		}
+		if got := test.naming.ColumnName(test.name); got != test.column {
+			t.Fatalf("%T column name of %s: want %s, got %s", test.naming, test.name, test.column, got)
+		}
+	}
+}
@@ -5,4 +5,31 @@ type Options struct {
 	TableNamePrefix string
 	StructName      string
 	TagName         string
+
+	// Schema qualifies the table in all the queries, eg. "tenant1"."product".
+	Schema string
+
+	// TableName sets the table name, instead of the one generated from the struct name or returned by its TableName method.
+	TableName string
+
+	// PrefixPrimaryKey prefixes the primary key column with the table name (without TableNamePrefix), eg. product_id instead of id.
+	PrefixPrimaryKey bool
+
+	// Strict makes Err() return unsupported fields, unknown tag options and invalid tag values, which are otherwise
+	// only returned by Warnings().
+	Strict bool
+
+	// AutoTimestamps makes the database set CreatedAt and ModifiedAt fields to now() on insert, and ModifiedAt on every update.
+	// Integer fields get seconds since epoch.  CreatedAt and CreatedBy are never updated.
+	AutoTimestamps bool
+
+	// SoftDelete makes DeletedAt field a soft delete field, the same as when it is tagged with deleted.  Delete queries set it
+	// instead of removing rows, and SELECT queries skip rows where it is set.
+	SoftDelete bool
+
+	// Naming converts struct and field names to table and column names.  DefaultNaming is used when it is nil.
+	Naming Naming
+
+	// References are builders of other tables that can be referenced in the fk tag by their key, eg. sql:"fk:User".
+	References map[string]*Builder
 }
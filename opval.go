@@ -20,4 +20,9 @@ const (
 	OpGreaterOrEqual
 	OpLowerOrEqual
 	OpBit
+	OpContains
+	OpContainedBy
+	OpOverlap
+	OpAny
+	OpHasKey
 )
@@ -0,0 +1,191 @@
+package pgsqlbuilder
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const (
+	deletedExcluded = iota * 1
+	deletedIncluded
+	deletedOnly
+)
+
+// setSoftDeleteField makes delete queries set a field tagged with deleted, or DeletedAt field with SoftDelete option,
+// instead of removing rows.
+// Field must be nullable (not deleted when NULL), or an integer (not deleted when 0).
+func (b *Builder) setSoftDeleteField() {
+	fieldName := ""
+	for _, name := range b.fieldNames {
+		if b.fieldFlags[name]&FieldFlagSoftDelete > 0 {
+			fieldName = name
+			break
+		}
+	}
+
+	isTagged := fieldName != ""
+	if !isTagged {
+		if !b.softDelete {
+			return
+		}
+		_, ok := b.fieldColumnName["DeletedAt"]
+		if !ok {
+			return
+		}
+		fieldName = "DeletedAt"
+	}
+
+	// Type is not known when reflecting the tags stopped at an earlier field
+	fieldType, ok := b.fieldTypes[fieldName]
+	if !ok {
+		return
+	}
+
+	isNullable := b.fieldFlags[fieldName]&FieldFlagNullable > 0
+	if !isIntegerKind(fieldType.Kind()) && (fieldType != timeType || !isNullable) {
+		err := fmt.Errorf("%w: deleted requires a time pointer or an integer field", softDeleteError)
+		if isTagged {
+			b.addError(getTagBuilderError(fieldName, b.tagName, err))
+		} else if !isTagged {
+			b.addWarning(fieldName, err)
+		}
+		return
+	}
+
+	b.softDeleteField = fieldName
+	b.softDeleteValue = "now()"
+	if fieldType.Kind() != reflect.Struct {
+		b.softDeleteValue = "CAST(EXTRACT(EPOCH FROM now()) AS BIGINT)"
+	}
+
+	// Rows are deleted and restored only by the dedicated queries
+	if b.fieldFlags[fieldName]&FieldFlagReadOnly == 0 {
+		b.fieldFlags[fieldName] += FieldFlagReadOnly
+	}
+	if b.fieldFlags[fieldName]&FieldFlagSoftDelete == 0 {
+		b.fieldFlags[fieldName] += FieldFlagSoftDelete
+	}
+}
+
+// deletedCondition returns a condition matching deleted rows, or rows that are not deleted.
+// It is empty when the table does not have soft delete.
+func (b *Builder) deletedCondition(isDeleted bool) string {
+	if b.softDeleteField == "" {
+		return ""
+	}
+
+	column := fmt.Sprintf(`"%s"`, b.fieldColumnName[b.softDeleteField])
+	isNullable := b.fieldFlags[b.softDeleteField]&FieldFlagNullable > 0
+	switch {
+	case isNullable && isDeleted:
+		return column + " IS NOT NULL"
+	case isNullable:
+		return column + " IS NULL"
+	case isDeleted:
+		return column + "<>0"
+	default:
+		return column + "=0"
+	}
+}
+
+// scopeCondition returns a condition matching rows that are selected, which by default are the ones that are not deleted.
+func (b *Builder) scopeCondition() string {
+	switch b.deletedScope {
+	case deletedIncluded:
+		return ""
+	case deletedOnly:
+		return b.deletedCondition(true)
+	default:
+		return b.deletedCondition(false)
+	}
+}
+
+// softDeleteSet returns columns set when the row is deleted, or restored.
+func (b *Builder) softDeleteSet(isRestore bool) string {
+	column := fmt.Sprintf(`"%s"`, b.fieldColumnName[b.softDeleteField])
+	if !isRestore {
+		return joinNotEmpty(",", column+"="+b.softDeleteValue, b.autoValueSet(false))
+	}
+
+	if b.fieldFlags[b.softDeleteField]&FieldFlagNullable > 0 {
+		return joinNotEmpty(",", column+"=NULL", b.autoValueSet(false))
+	}
+
+	return joinNotEmpty(",", column+"=0", b.autoValueSet(false))
+}
+
+// andCondition adds a condition to the WHERE clause.
+func andCondition(where string, condition string) string {
+	if condition == "" {
+		return where
+	}
+	if where == "" {
+		return condition
+	}
+
+	return fmt.Sprintf("(%s) AND %s", where, condition)
+}
+
+// WithDeleted returns a copy of the Builder that selects deleted rows as well.
+func (b *Builder) WithDeleted() *Builder {
+	builder := *b
+	builder.deletedScope = deletedIncluded
+	builder.buildQueries()
+
+	return &builder
+}
+
+// OnlyDeleted returns a copy of the Builder that selects deleted rows only.
+func (b *Builder) OnlyDeleted() *Builder {
+	builder := *b
+	builder.deletedScope = deletedOnly
+	builder.buildQueries()
+
+	return &builder
+}
+
+// RestoreByID returns an SQL query for restoring a deleted object by its ID.
+// Empty string is returned when there is no primary key or no soft delete field.
+func (b *Builder) RestoreByID() string {
+	return queryStatement(b.queryRestoreByID)
+}
+
+// HardDeleteByID returns an SQL query for removing an object by its ID, even when the table has soft delete.
+// The row is removed whether it is deleted or not, eg. to purge it.
+// Empty string is returned when there is no primary key.
+func (b *Builder) HardDeleteByID() string {
+	return queryStatement(b.queryHardDeleteByID)
+}
+
+// Restore returns an UPDATE query restoring deleted rows that match WHERE condition built from 'filters' (field-value pairs).
+// Struct fields in 'filters' argument are sorted alphabetically. Hence, when used with database connection, their values (or pointers to it) must be sorted as well.
+func (b *Builder) Restore(filters *Filters) (string, error) {
+	if b.softDeleteField == "" {
+		return "", getClauseBuilderError("set", "deleted field", noSoftDeleteError)
+	}
+
+	qWhere, err := b.queryFilters(filters, 1)
+	if err != nil {
+		return "", getClauseBuilderError("where", "filters", err)
+	}
+
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s;", b.tableName, b.softDeleteSet(true), andCondition(qWhere, b.deletedCondition(true))), nil
+}
+
+// HardDelete returns a DELETE query with WHERE condition built from 'filters' (field-value pairs), which removes rows even when
+// the table has soft delete.  Rows are removed whether they are deleted or not, and filters can limit it to deleted ones.
+// Struct fields in 'filters' argument are sorted alphabetically. Hence, when used with database connection, their values (or pointers to it) must be sorted as well.
+func (b *Builder) HardDelete(filters *Filters) (string, error) {
+	query := fmt.Sprintf("DELETE FROM %s", b.tableName)
+
+	qWhere, err := b.queryFilters(filters, 1)
+	if err != nil {
+		return "", getClauseBuilderError("where", "filters", err)
+	}
+
+	if qWhere != "" {
+		query += " WHERE " + qWhere
+	}
+
+	return query + ";", nil
+}
@@ -0,0 +1,100 @@
+package pgsqlbuilder
+
+import (
+	"reflect"
+	"strings"
+)
+
+// structField is a struct field that becomes a column.  Fields of embedded structs are promoted to the struct,
+// and fields of nested structs with prefix tag are named with a path, eg. Address.City.
+type structField struct {
+	name         string
+	field        reflect.StructField
+	index        []int
+	columnPrefix string
+}
+
+// structFields returns fields of a struct that become columns, in the order they are defined.
+// When a promoted field has the same name as a field of the struct, the one that is less nested wins, as in Go.
+func structFields(t reflect.Type, tagName string) []structField {
+	fields, _ := structFieldsWithSkipped(t, tagName)
+	return fields
+}
+
+// structFieldsWithSkipped returns fields of a struct that become columns, and fields that are skipped because
+// their type is not supported.
+func structFieldsWithSkipped(t reflect.Type, tagName string) ([]structField, []structField) {
+	var skipped []structField
+	fields := appendStructFields(nil, &skipped, t, tagName, "", "", nil)
+
+	positions := make(map[string]int, len(fields))
+	result := make([]structField, 0, len(fields))
+	for _, f := range fields {
+		pos, ok := positions[f.name]
+		if !ok {
+			positions[f.name] = len(result)
+			result = append(result, f)
+			continue
+		}
+
+		if len(f.index) < len(result[pos].index) {
+			result[pos] = f
+		}
+	}
+
+	return result, skipped
+}
+
+func appendStructFields(fields []structField, skipped *[]structField, t reflect.Type, tagName string, namePrefix string, columnPrefix string, index []int) []structField {
+	for j := 0; j < t.NumField(); j++ {
+		field := t.Field(j)
+		fieldIndex := append(append([]int{}, index...), j)
+
+		// Field tagged with "-" is not a column, even if it is of a supported type
+		if field.Tag.Get(tagName) == "-" {
+			continue
+		}
+
+		prefix, hasPrefix := structFieldPrefix(field.Tag.Get(tagName))
+		isStruct := field.Type.Kind() == reflect.Struct && field.Type != timeType && !isValuerType(field.Type)
+
+		// Embedded struct is flattened, and its fields are promoted
+		if field.Anonymous && isStruct {
+			fields = appendStructFields(fields, skipped, field.Type, tagName, namePrefix, columnPrefix+prefix, fieldIndex)
+			continue
+		}
+
+		// Nested struct is flattened only when it has a prefix for its columns, and otherwise it is a JSONB column
+		if hasPrefix && isStruct {
+			fields = appendStructFields(fields, skipped, field.Type, tagName, namePrefix+field.Name+".", columnPrefix+prefix, fieldIndex)
+			continue
+		}
+
+		f := structField{
+			name:         namePrefix + field.Name,
+			field:        field,
+			index:        fieldIndex,
+			columnPrefix: columnPrefix,
+		}
+		if !isColumnField(field) {
+			*skipped = append(*skipped, f)
+			continue
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields
+}
+
+// structFieldPrefix returns a value of prefix tag option.
+func structFieldPrefix(tag string) (string, bool) {
+	for _, opt := range splitTag(tag) {
+		prefix, ok := strings.CutPrefix(opt, "prefix:")
+		if ok {
+			return prefix, true
+		}
+	}
+
+	return "", false
+}
@@ -1,7 +1,64 @@
 package pgsqlbuilder
 
-import "regexp"
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"regexp"
+	"time"
+)
+
+var (
+	regexpFieldInRaw      = regexp.MustCompile(`(^|[^a-zA-Z0-9_."'])\.([a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z0-9_]+)*)`)
+	regexpJSONKey         = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+	regexpNumericType     = regexp.MustCompile(`^(NUMERIC|DECIMAL)(\(([0-9]+)(,([0-9]+))?\))?$`)
+	regexpNumericValue    = regexp.MustCompile(`^-?([0-9]+)(\.([0-9]+))?$`)
+	regexpStringTypeSize  = regexp.MustCompile(`\(([0-9]+)\)$`)
+	regexpDefaultFunction = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.]*\((` + defaultFunctionArg + `(,` + defaultFunctionArg + `)*)?\)$`)
+	regexpCheckOperator   = regexp.MustCompile(`^\(?\s*(<>|!=|>=|<=|=|<|>)`)
+)
+
+// maxIdentifierLength is the maximum length of PostgreSQL identifier in bytes, and longer ones are truncated by the server.
+const maxIdentifierLength = 63
+
+// defaultFunctionArg is an argument of a function call in a default value, which is a number, a string literal, an identifier
+// or a call without arguments, eg. nextval('seq') or date_trunc('day', now()).
+const defaultFunctionArg = `\s*(-?[0-9]+(\.[0-9]+)?|'([^']|'')*'|[a-zA-Z_][a-zA-Z0-9_.]*(\(\))?)\s*`
+
+var (
+	integerColumnBits = map[string]int{
+		"SMALLINT": 16,
+		"INTEGER":  32,
+		"BIGINT":   64,
+	}
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+var (
+	foreignKeyActions = map[string]string{
+		"cascade":     "CASCADE",
+		"restrict":    "RESTRICT",
+		"noaction":    "NO ACTION",
+		"no_action":   "NO ACTION",
+		"setnull":     "SET NULL",
+		"set_null":    "SET NULL",
+		"setdefault":  "SET DEFAULT",
+		"set_default": "SET DEFAULT",
+	}
+)
 
 var (
-	regexpFieldInRaw = regexp.MustCompile(`\.[a-zA-Z0-9_]+`)
+	indexMethods = map[string]bool{
+		"btree":  true,
+		"hash":   true,
+		"gin":    true,
+		"gist":   true,
+		"spgist": true,
+		"brin":   true,
+	}
 )
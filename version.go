@@ -0,0 +1,107 @@
+package pgsqlbuilder
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// VersionConflictError is returned by CheckVersion when a query with an expected version did not change any row, because
+// the row has been changed by someone else in the meantime (or it does not exist anymore).
+// Table is the name of the table without the schema, which is in Schema when the table is schema-qualified.
+type VersionConflictError struct {
+	Schema   string
+	Table    string
+	Expected int64
+}
+
+func (e *VersionConflictError) Error() string {
+	table := e.Table
+	if e.Schema != "" {
+		table = e.Schema + "." + e.Table
+	}
+
+	return fmt.Sprintf("version conflict: row in %s table is not at version %d", table, e.Expected)
+}
+
+// setFieldVersion makes the field a version for optimistic locking, which is incremented by every update.
+func (b *Builder) setFieldVersion(fieldName string, fieldType reflect.Type) {
+	if b.versionField == fieldName {
+		return
+	}
+
+	var err error
+	switch {
+	case !isIntegerKind(fieldType.Kind()):
+		err = fmt.Errorf("%w: version requires an integer field", versionError)
+	case b.versionField != "":
+		err = fmt.Errorf("%w: %s is a version already", versionError, b.versionField)
+	}
+	if err != nil {
+		b.addError(getTagBuilderError(fieldName, b.tagName, err))
+		return
+	}
+
+	b.versionField = fieldName
+	b.fieldFlags[fieldName] += FieldFlagVersion
+}
+
+// versionColumn returns quoted version column, or an empty string when there is no version field.
+func (b *Builder) versionColumn() string {
+	if b.versionField == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(`"%s"`, b.fieldColumnName[b.versionField])
+}
+
+// versionSet returns the version incremented by one.  In upserts, the current version is qualified with the table name,
+// as it would be ambiguous with the EXCLUDED one.
+func (b *Builder) versionSet(isUpsert bool) string {
+	column := b.versionColumn()
+	if column == "" {
+		return ""
+	}
+
+	if isUpsert {
+		return fmt.Sprintf("%s=%s.%s+1", column, b.tableName, column)
+	}
+
+	return fmt.Sprintf("%s=%s+1", column, column)
+}
+
+// versionValueNumber returns the number of placeholder of the expected version in values map, which are sorted by field names.
+// It returns 0 when the version is not there.
+func (b *Builder) versionValueNumber(values map[string]interface{}) int {
+	_, ok := values[b.versionField]
+	if b.versionField == "" || !ok {
+		return 0
+	}
+
+	number := 1
+	for fieldName := range values {
+		if fieldName < b.versionField {
+			number++
+		}
+	}
+
+	return number
+}
+
+// VersionField returns name of the field tagged with version, or an empty string when there is none.
+func (b *Builder) VersionField() string {
+	return b.versionField
+}
+
+// CheckVersion takes number of rows changed by UpdateByID(), InsertOnConflictUpdate() or Update() with the expected version,
+// and returns *VersionConflictError when it is zero, which means the update was lost.
+func (b *Builder) CheckVersion(rowsAffected int64, expected int64) error {
+	if b.versionField == "" || rowsAffected > 0 {
+		return nil
+	}
+
+	return &VersionConflictError{
+		Schema:   b.schema,
+		Table:    b.tableBaseName,
+		Expected: expected,
+	}
+}